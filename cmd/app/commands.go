@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/backup"
+	"github.com/gerald-lbn/refrain/pkg/lyrics"
+	"github.com/gerald-lbn/refrain/pkg/music"
+	"github.com/gerald-lbn/refrain/pkg/repository"
+	"github.com/gerald-lbn/refrain/pkg/services"
+)
+
+// command is a command-line operation run against the container.
+type command func(ctx context.Context, c *services.Container, args []string) error
+
+// commands lists the available commands by name.
+var commands = map[string]command{
+	"backup":           backupCommand,
+	"migrate":          migrateCommand,
+	"normalize-lyrics": normalizeLyricsCommand,
+	"offset":           offsetCommand,
+	"restore":          restoreCommand,
+}
+
+// runCommand runs the command with the given name and terminates the application if it fails.
+func runCommand(name string, args []string) {
+	cmd, ok := commands[name]
+	if !ok {
+		names := make([]string, 0, len(commands))
+		for n := range commands {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		fmt.Fprintf(os.Stderr, "unknown command %q, available commands: %v\n", name, names)
+		os.Exit(2)
+	}
+
+	c := services.NewCommandContainer()
+	ctx := context.Background()
+
+	// The migrate and restore commands manage the schema themselves, every other command expects it up to date
+	var err error
+	if name != "migrate" && name != "restore" {
+		err = migrateDatabase(ctx, c)
+	}
+	if err == nil {
+		err = cmd(ctx, c, args)
+	}
+	fatal("shutdown failed", c.Shutdown())
+	fatal(fmt.Sprintf("%s command failed", name), err)
+}
+
+// offsetCommand shifts the synced lyrics of a track or album.
+func offsetCommand(ctx context.Context, c *services.Container, args []string) error {
+	fs := flag.NewFlagSet("offset", flag.ExitOnError)
+	id := fs.Int64("id", 0, "ID of the track")
+	path := fs.String("path", "", "path of the track, used when no ID is given")
+	offset := fs.Duration("offset", 0, "delay to apply to the lyrics, negative to make them appear earlier (e.g. 250ms, -1.5s)")
+	mode := fs.String("mode", string(lyrics.OffsetModeRewrite), "how to apply the offset: rewrite or tag")
+	album := fs.Bool("album", false, "apply the offset to every track of the album")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *id == 0 {
+		if *path == "" {
+			return errors.New("either -id or -path is required")
+		}
+
+		track, err := repository.New(c.Database).GetTrackByPath(ctx, *path)
+		if err != nil {
+			return err
+		}
+		*id = track.ID
+	}
+
+	tracks, err := lyrics.Shift(ctx, c, lyrics.ShiftOptions{
+		TrackID:    *id,
+		Offset:     *offset,
+		Mode:       lyrics.OffsetMode(*mode),
+		WholeAlbum: *album,
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(tracks)
+}
+
+// backupCommand copies the database to the given file, or to a new backup of the backup directory.
+func backupCommand(ctx context.Context, c *services.Container, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	output := fs.String("output", "", "file to write the backup to, a new file of the backup directory by default")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *output == "" {
+		p, err := c.Backups.Run(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Println(p)
+		return nil
+	}
+
+	if err := backup.Backup(ctx, c.Database, *output); err != nil {
+		return err
+	}
+	fmt.Println(*output)
+	return nil
+}
+
+// restoreCommand replaces the database with a backup, then brings its schema up to date.
+// The application must be stopped while restoring.
+func restoreCommand(ctx context.Context, c *services.Container, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: restore [-keep-current=false] BACKUP")
+		fs.PrintDefaults()
+	}
+	keepCurrent := fs.Bool("keep-current", true, "back the current database up to the backup directory before replacing it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("the backup to restore is required")
+	}
+
+	if *keepCurrent {
+		p := filepath.Join(c.Config.Database.Backup.Directory, "pre-restore-"+backup.FileName(time.Now()))
+		if err := backup.Backup(ctx, c.Database, p); err != nil {
+			return fmt.Errorf("failed to back up the current database: %w", err)
+		}
+		slog.Info("backed up the current database", "path", p)
+	}
+
+	// A backup taken by a newer version of the application can't be used by this one
+	err := backup.Restore(ctx, c.Database, fs.Arg(0), func(ctx context.Context, source *sql.DB) error {
+		var version uint64
+		if err := source.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version); err != nil {
+			return fmt.Errorf("failed to read the schema version of the backup: %w", err)
+		}
+		return c.Migrator.CheckVersion(version)
+	})
+	if err != nil {
+		return err
+	}
+	slog.Info("restored the database", "backup", fs.Arg(0))
+
+	// The backup may predate migrations of this version
+	return migrateDatabase(ctx, c)
+}
+
+// migrateCommand applies or rolls back migrations, or lists their status.
+func migrateCommand(ctx context.Context, c *services.Container, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: migrate up|down|status")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch fs.Arg(0) {
+	case "up":
+		return migrateDatabase(ctx, c)
+	case "down":
+		m, err := c.Migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("rolled back migration", "version", m.Version, "name", m.Name)
+		return nil
+	case "status":
+		statuses, err := c.Migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(statuses)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown migrate action %q", fs.Arg(0))
+	}
+}
+
+// normalizeLyricsCommand converts the lyrics files of the libraries, or of the given paths, to UTF-8.
+func normalizeLyricsCommand(ctx context.Context, c *services.Container, args []string) error {
+	fs := flag.NewFlagSet("normalize-lyrics", flag.ExitOnError)
+	lineEnding := fs.String("line-ending", c.Config.Lyrics.Encoding.LineEnding, "line terminator of the normalized files: lf or crlf")
+	dryRun := fs.Bool("dry-run", false, "report the files to normalize without modifying them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ending, err := music.ParseLineEnding(*lineEnding)
+	if err != nil {
+		return err
+	}
+
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = append([]string{}, c.Config.Libraries.Paths...)
+		if c.Config.Lyrics.Root != "" {
+			paths = append(paths, c.Config.Lyrics.Root)
+		}
+	}
+
+	files, err := lyrics.NormalizeEncodings(paths, c.LyricsPaths, ending, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(files)
+}
@@ -10,18 +10,31 @@ import (
 
 	"github.com/gerald-lbn/refrain/pkg/handlers"
 	"github.com/gerald-lbn/refrain/pkg/log"
+	"github.com/gerald-lbn/refrain/pkg/lyrics"
+	"github.com/gerald-lbn/refrain/pkg/repository"
 	"github.com/gerald-lbn/refrain/pkg/router"
 	"github.com/gerald-lbn/refrain/pkg/services"
 	"github.com/gerald-lbn/refrain/pkg/tasks"
 )
 
 func main() {
+	// Run a command instead of the server when one is given.
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1], os.Args[2:])
+		return
+	}
+
 	// Start a new container.
 	c := services.NewContainer()
 	defer func() {
 		fatal("shutdown failed", c.Shutdown())
 	}()
 
+	// Bring the database schema up to date.
+	ctx := context.Background()
+	fatal("failed to migrate the database", migrateDatabase(ctx, c))
+	fatal("failed to reset interrupted lyrics searches", resetInterruptedSearches(ctx, c))
+
 	// Build the router.
 	if err := router.BuildRouter(c); err != nil {
 		fatal("failed to build the router", err)
@@ -30,10 +43,26 @@ func main() {
 	// Register all task queues.
 	tasks.Register(c)
 
+	// Link the tracks persisted before artists and albums were stored to theirs.
+	if count, err := tasks.EnqueueMissingEntities(ctx, c); err != nil {
+		fatal("failed to enqueue the tracks missing their artist or album", err)
+	} else if count > 0 {
+		slog.Info("enqueued tracks missing their artist or album", "tracks", count)
+	}
+
 	// Start the task runner to execute queued tasks.
-	ctx := context.Background()
 	c.Tasks.Start(ctx)
 
+	// Back the database up on a schedule.
+	if c.Config.Database.Backup.Enabled {
+		c.Backups.Start(ctx)
+	}
+
+	// Record the history of the lyrics coverage.
+	if c.Config.Stats.SnapshotInterval > 0 {
+		c.Stats.Start(ctx)
+	}
+
 	// Start the server.
 	go func() {
 		addr := fmt.Sprintf("%s:%d", c.Config.HTTP.Hostname, c.Config.HTTP.Port)
@@ -67,6 +96,40 @@ func main() {
 	<-quit
 }
 
+// migrateDatabase applies the pending migrations to the database.
+func migrateDatabase(ctx context.Context, c *services.Container) error {
+	migrations, err := c.Migrator.Up(ctx)
+	for _, m := range migrations {
+		slog.Info("applied migration", "version", m.Version, "name", m.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The full-text index of the lyrics needs SQLite to be built with FTS5, searches are slower without it
+	indexed, err := lyrics.HasSearchIndex(ctx, c.Database)
+	if err != nil {
+		return err
+	}
+	if !indexed {
+		slog.Warn("full-text search of lyrics is unavailable, build with -tags sqlite_fts5 to enable it")
+	}
+	return nil
+}
+
+// resetInterruptedSearches moves the tracks left searching by a run that stopped mid-search back to
+// pending. Their task was interrupted as well and runs again once it is released.
+func resetInterruptedSearches(ctx context.Context, c *services.Container) error {
+	count, err := repository.New(c.Database).ResetSearchingLyricsStatuses(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		slog.Info("reset interrupted lyrics searches", "tracks", count)
+	}
+	return nil
+}
+
 // fatal logs an error and terminates the application, if the error is not nil.
 func fatal(msg string, err error) {
 	if err != nil {
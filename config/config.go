@@ -37,10 +37,14 @@ type (
 	// Config stores complete application configuration.
 	Config struct {
 		App       AppConfig
+		Covers    CoversConfig
 		Database  DatabaseConfig
 		HTTP      HTTPConfig
 		Libraries LibrariesConfig
+		Lyrics    LyricsConfig
+		Metadata  MetadataConfig
 		Redis     RedisConfig
+		Stats     StatsConfig
 		Tasks     TasksConfig
 	}
 
@@ -52,11 +56,35 @@ type (
 		Timeout       time.Duration
 	}
 
+	// CoversConfig stores configuration for cover art thumbnails.
+	CoversConfig struct {
+		// CacheDirectory stores the resized cover art.
+		CacheDirectory string
+		// Size is the maximum width and height of the thumbnails in pixels.
+		Size int
+	}
+
 	// DatabaseConfig stores the database configuration.
 	DatabaseConfig struct {
 		Driver         string
 		Connection     string
 		TestConnection string
+		// Backup takes online backups of the database while the application runs.
+		Backup DatabaseBackupConfig
+	}
+
+	// DatabaseBackupConfig stores configuration for the scheduled backups of the database.
+	DatabaseBackupConfig struct {
+		Enabled bool
+		// Directory stores the backups, named after the time they were taken.
+		Directory string
+		// Interval is the time between two backups.
+		Interval time.Duration
+		// Retention is the number of backups kept, the oldest ones being removed.
+		Retention int
+		// Download serves a backup of the database at /api/backup. The API has no authentication,
+		// so it is off by default.
+		Download bool
 	}
 
 	// HTTPConfig stores HTTP configuration.
@@ -75,11 +103,88 @@ type (
 		Paths []string `mapstructure:"paths"`
 	}
 
+	// LyricsConfig stores configuration for lyrics handling.
+	LyricsConfig struct {
+		// PathTemplate is the pattern of lyrics file paths, see music.LyricsPathTemplate.
+		PathTemplate string
+		// CuePathTemplate is the pattern of lyrics file paths for the tracks of CUE sheets.
+		CuePathTemplate string
+		// Root is a writable directory for lyrics, referenced by {root} in PathTemplate.
+		Root string
+		// ExtractEmbedded writes lyrics found in the audio tags to sidecar files.
+		ExtractEmbedded bool
+		// Embed stores downloaded lyrics in the audio tags.
+		Embed LyricsEmbedConfig
+		// Validation checks downloaded lyrics against the track before writing them.
+		Validation LyricsValidationConfig
+		// Normalization simplifies the tags to search lyrics when the raw tags are not found.
+		Normalization LyricsNormalizationConfig
+		// Encoding converts lyrics files to UTF-8.
+		Encoding LyricsEncodingConfig
+	}
+
+	// LyricsEmbedConfig stores configuration for embedding lyrics into audio tags.
+	LyricsEmbedConfig struct {
+		Enabled bool
+		// DryRun logs the tags that would be written without modifying audio files.
+		DryRun bool
+		// BackupDirectory stores the original tags of every modified audio file.
+		BackupDirectory string
+	}
+
+	// LyricsValidationConfig stores configuration for the validation of downloaded lyrics.
+	LyricsValidationConfig struct {
+		Enabled bool
+		// DurationTolerance is how much the lyrics and the track durations may differ.
+		DurationTolerance time.Duration
+		// Reject skips writing suspicious lyrics, which config.yml enables. Without it, suspicious
+		// lyrics are written and only flagged with their issue.
+		Reject bool
+	}
+
+	// LyricsEncodingConfig stores configuration for the conversion of lyrics files to UTF-8.
+	LyricsEncodingConfig struct {
+		// Normalize converts the lyrics files added to the libraries as soon as they are detected.
+		// Files are rewritten in place without backup, so it is off by default.
+		Normalize bool
+		// LineEnding is the line terminator of normalized files, either lf or crlf.
+		LineEnding string
+	}
+
+	// LyricsNormalizationConfig stores the rules used to normalize tags when searching lyrics,
+	// see music.NormalizationRules.
+	LyricsNormalizationConfig struct {
+		Enabled           bool
+		FoldUnicode       bool
+		StripBrackets     bool
+		StripSuffixes     bool
+		ExtractFeaturing  bool
+		AmpersandVariants bool
+		// Keywords mark the bracketed parts and suffixes to strip, such as "remaster" or "live".
+		Keywords []string `mapstructure:"keywords"`
+		// MaxQueries caps the normalized queries sent to the provider for each track.
+		MaxQueries int
+	}
+
+	// MetadataConfig stores configuration for the extraction of track metadata.
+	MetadataConfig struct {
+		// PathPatterns infer the fields missing from the tags from the path of the audio files,
+		// see music.PathPattern. The first matching pattern is used.
+		PathPatterns []string `mapstructure:"pathPatterns"`
+	}
+
 	// RedisConfig stores configuration for redis
 	RedisConfig struct {
 		Addr string
 	}
 
+	// StatsConfig stores configuration for the history of the library statistics.
+	StatsConfig struct {
+		// SnapshotInterval is the time between two snapshots of the lyrics coverage, zero disables them.
+		// Snapshots taken on the same day replace each other.
+		SnapshotInterval time.Duration
+	}
+
 	// TasksConfig stores the tasks configuration.
 	TasksConfig struct {
 		GoRoutines      int
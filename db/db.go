@@ -0,0 +1,9 @@
+// Package db embeds the SQL migrations of the application.
+package db
+
+import "embed"
+
+// Migrations holds the dbmate-style migration files, applied in order of version.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
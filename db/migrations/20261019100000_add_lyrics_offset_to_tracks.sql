@@ -0,0 +1,5 @@
+-- migrate:up
+ALTER TABLE tracks ADD COLUMN lyrics_offset INTEGER NOT NULL DEFAULT 0;
+
+-- migrate:down
+ALTER TABLE tracks DROP COLUMN lyrics_offset;
@@ -0,0 +1,5 @@
+-- migrate:up
+ALTER TABLE tracks ADD COLUMN lyrics_issue TEXT;
+
+-- migrate:down
+ALTER TABLE tracks DROP COLUMN lyrics_issue;
@@ -0,0 +1,29 @@
+-- migrate:up
+ALTER TABLE tracks ADD COLUMN track_number INTEGER;
+ALTER TABLE tracks ADD COLUMN disc_number INTEGER;
+ALTER TABLE tracks ADD COLUMN release_date TEXT;
+ALTER TABLE tracks ADD COLUMN year INTEGER;
+ALTER TABLE tracks ADD COLUMN genre TEXT;
+ALTER TABLE tracks ADD COLUMN composer TEXT;
+ALTER TABLE tracks ADD COLUMN musicbrainz_recording_id TEXT;
+ALTER TABLE tracks ADD COLUMN musicbrainz_release_id TEXT;
+ALTER TABLE tracks ADD COLUMN isrc TEXT;
+
+CREATE INDEX idx_tracks_musicbrainz_recording_id ON tracks(musicbrainz_recording_id);
+CREATE INDEX idx_tracks_musicbrainz_release_id ON tracks(musicbrainz_release_id);
+CREATE INDEX idx_tracks_isrc ON tracks(isrc);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_tracks_isrc;
+DROP INDEX IF EXISTS idx_tracks_musicbrainz_release_id;
+DROP INDEX IF EXISTS idx_tracks_musicbrainz_recording_id;
+
+ALTER TABLE tracks DROP COLUMN isrc;
+ALTER TABLE tracks DROP COLUMN musicbrainz_release_id;
+ALTER TABLE tracks DROP COLUMN musicbrainz_recording_id;
+ALTER TABLE tracks DROP COLUMN composer;
+ALTER TABLE tracks DROP COLUMN genre;
+ALTER TABLE tracks DROP COLUMN year;
+ALTER TABLE tracks DROP COLUMN release_date;
+ALTER TABLE tracks DROP COLUMN disc_number;
+ALTER TABLE tracks DROP COLUMN track_number;
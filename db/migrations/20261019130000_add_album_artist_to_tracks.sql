@@ -0,0 +1,9 @@
+-- migrate:up
+ALTER TABLE tracks ADD COLUMN album_artist TEXT;
+
+CREATE INDEX idx_tracks_album_artist ON tracks(album_artist);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_tracks_album_artist;
+
+ALTER TABLE tracks DROP COLUMN album_artist;
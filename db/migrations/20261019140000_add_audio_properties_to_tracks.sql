@@ -0,0 +1,17 @@
+-- migrate:up
+ALTER TABLE tracks ADD COLUMN format TEXT;
+ALTER TABLE tracks ADD COLUMN bitrate INTEGER;
+ALTER TABLE tracks ADD COLUMN sample_rate INTEGER;
+ALTER TABLE tracks ADD COLUMN channels INTEGER;
+ALTER TABLE tracks ADD COLUMN file_size INTEGER;
+
+CREATE INDEX idx_tracks_format ON tracks(format);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_tracks_format;
+
+ALTER TABLE tracks DROP COLUMN file_size;
+ALTER TABLE tracks DROP COLUMN channels;
+ALTER TABLE tracks DROP COLUMN sample_rate;
+ALTER TABLE tracks DROP COLUMN bitrate;
+ALTER TABLE tracks DROP COLUMN format;
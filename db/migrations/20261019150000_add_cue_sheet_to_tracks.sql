@@ -0,0 +1,15 @@
+-- migrate:up
+ALTER TABLE tracks ADD COLUMN audio_path TEXT;
+ALTER TABLE tracks ADD COLUMN cue_sheet_path TEXT;
+ALTER TABLE tracks ADD COLUMN start_offset REAL NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_tracks_audio_path ON tracks(audio_path);
+CREATE INDEX idx_tracks_cue_sheet_path ON tracks(cue_sheet_path);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_tracks_cue_sheet_path;
+DROP INDEX IF EXISTS idx_tracks_audio_path;
+
+ALTER TABLE tracks DROP COLUMN start_offset;
+ALTER TABLE tracks DROP COLUMN cue_sheet_path;
+ALTER TABLE tracks DROP COLUMN audio_path;
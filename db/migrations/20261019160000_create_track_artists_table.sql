@@ -0,0 +1,21 @@
+-- migrate:up
+CREATE TABLE track_artists (
+    track_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    artist TEXT NOT NULL,
+    PRIMARY KEY (track_id, position),
+    FOREIGN KEY (track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_track_artists_artist ON track_artists(artist COLLATE NOCASE);
+
+-- Foreign keys are not enforced on every connection, so remove the artists of deleted tracks explicitly
+CREATE TRIGGER trg_tracks_delete_artists AFTER DELETE ON tracks
+BEGIN
+    DELETE FROM track_artists WHERE track_id = OLD.id;
+END;
+
+-- migrate:down
+DROP TRIGGER IF EXISTS trg_tracks_delete_artists;
+DROP INDEX IF EXISTS idx_track_artists_artist;
+DROP TABLE IF EXISTS track_artists;
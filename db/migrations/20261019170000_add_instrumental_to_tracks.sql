@@ -0,0 +1,7 @@
+-- migrate:up
+ALTER TABLE tracks ADD COLUMN instrumental BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE tracks ADD COLUMN instrumental_source TEXT;
+
+-- migrate:down
+ALTER TABLE tracks DROP COLUMN instrumental_source;
+ALTER TABLE tracks DROP COLUMN instrumental;
@@ -0,0 +1,22 @@
+-- migrate:up
+CREATE TABLE lyrics (
+    track_id INTEGER PRIMARY KEY,
+    plain_lyrics TEXT,
+    synced_lyrics TEXT,
+    provider TEXT NOT NULL,
+    provider_id INTEGER,
+    fetched_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    content_hash TEXT NOT NULL,
+    edited BOOLEAN NOT NULL DEFAULT 0,
+    FOREIGN KEY (track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+-- Foreign keys are not enforced on every connection, so remove the lyrics of deleted tracks explicitly
+CREATE TRIGGER trg_tracks_delete_lyrics AFTER DELETE ON tracks
+BEGIN
+    DELETE FROM lyrics WHERE track_id = OLD.id;
+END;
+
+-- migrate:down
+DROP TRIGGER IF EXISTS trg_tracks_delete_lyrics;
+DROP TABLE IF EXISTS lyrics;
@@ -0,0 +1,64 @@
+-- migrate:up
+CREATE TABLE artists (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    musicbrainz_id TEXT UNIQUE
+);
+
+CREATE INDEX idx_artists_name ON artists(name COLLATE NOCASE);
+
+CREATE TABLE albums (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    artist_id INTEGER,
+    musicbrainz_id TEXT UNIQUE,
+    year INTEGER,
+    FOREIGN KEY (artist_id) REFERENCES artists(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_albums_title ON albums(title COLLATE NOCASE);
+CREATE INDEX idx_albums_artist_id ON albums(artist_id);
+
+ALTER TABLE tracks ADD COLUMN artist_id INTEGER;
+ALTER TABLE tracks ADD COLUMN album_id INTEGER;
+
+CREATE INDEX idx_tracks_artist_id ON tracks(artist_id);
+CREATE INDEX idx_tracks_album_id ON tracks(album_id);
+
+-- Albums and artists only exist through their tracks, remove them along with their last track
+CREATE TRIGGER trg_tracks_delete_albums AFTER DELETE ON tracks
+BEGIN
+    DELETE FROM albums WHERE id = OLD.album_id AND NOT EXISTS (SELECT 1 FROM tracks WHERE album_id = OLD.album_id);
+    DELETE FROM artists WHERE id = OLD.artist_id
+        AND NOT EXISTS (SELECT 1 FROM tracks WHERE artist_id = OLD.artist_id)
+        AND NOT EXISTS (SELECT 1 FROM albums WHERE artist_id = OLD.artist_id);
+END;
+
+CREATE TRIGGER trg_tracks_update_albums AFTER UPDATE OF artist_id, album_id ON tracks
+BEGIN
+    DELETE FROM albums WHERE id = OLD.album_id AND NOT EXISTS (SELECT 1 FROM tracks WHERE album_id = OLD.album_id);
+    DELETE FROM artists WHERE id = OLD.artist_id
+        AND NOT EXISTS (SELECT 1 FROM tracks WHERE artist_id = OLD.artist_id)
+        AND NOT EXISTS (SELECT 1 FROM albums WHERE artist_id = OLD.artist_id);
+END;
+
+CREATE TRIGGER trg_albums_delete_artists AFTER DELETE ON albums
+BEGIN
+    DELETE FROM artists WHERE id = OLD.artist_id
+        AND NOT EXISTS (SELECT 1 FROM tracks WHERE artist_id = OLD.artist_id)
+        AND NOT EXISTS (SELECT 1 FROM albums WHERE artist_id = OLD.artist_id);
+END;
+
+-- migrate:down
+DROP TRIGGER IF EXISTS trg_albums_delete_artists;
+DROP TRIGGER IF EXISTS trg_tracks_update_albums;
+DROP TRIGGER IF EXISTS trg_tracks_delete_albums;
+DROP INDEX IF EXISTS idx_tracks_album_id;
+DROP INDEX IF EXISTS idx_tracks_artist_id;
+ALTER TABLE tracks DROP COLUMN album_id;
+ALTER TABLE tracks DROP COLUMN artist_id;
+DROP INDEX IF EXISTS idx_albums_artist_id;
+DROP INDEX IF EXISTS idx_albums_title;
+DROP TABLE IF EXISTS albums;
+DROP INDEX IF EXISTS idx_artists_name;
+DROP TABLE IF EXISTS artists;
@@ -0,0 +1,27 @@
+-- migrate:up
+CREATE TABLE lyrics_attempts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    track_id INTEGER NOT NULL,
+    attempted_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    provider TEXT NOT NULL,
+    track_name TEXT,
+    artist_name TEXT,
+    album_name TEXT,
+    duration INTEGER,
+    outcome TEXT NOT NULL,
+    error TEXT,
+    provider_id INTEGER,
+    FOREIGN KEY (track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_lyrics_attempts_track_id ON lyrics_attempts(track_id, attempted_at);
+
+-- Foreign keys are not enforced on every connection, so remove the attempts of deleted tracks explicitly
+CREATE TRIGGER trg_tracks_delete_lyrics_attempts AFTER DELETE ON tracks
+BEGIN
+    DELETE FROM lyrics_attempts WHERE track_id = OLD.id;
+END;
+
+-- migrate:down
+DROP TRIGGER IF EXISTS trg_tracks_delete_lyrics_attempts;
+DROP TABLE IF EXISTS lyrics_attempts;
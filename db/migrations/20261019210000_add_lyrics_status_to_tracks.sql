@@ -0,0 +1,17 @@
+-- migrate:up
+ALTER TABLE tracks ADD COLUMN lyrics_status TEXT NOT NULL DEFAULT 'pending';
+
+-- Start from what the files of each track tell, searches not being known yet
+UPDATE tracks SET lyrics_status = CASE
+    WHEN instrumental = 1 THEN 'instrumental'
+    WHEN EXISTS (SELECT 1 FROM lyrics WHERE lyrics.track_id = tracks.id AND lyrics.edited = 1) THEN 'manual'
+    WHEN has_plain_lyrics = 1 AND has_synced_lyrics = 1 THEN 'found'
+    WHEN has_plain_lyrics = 1 OR has_synced_lyrics = 1 THEN 'partial'
+    ELSE 'pending'
+END;
+
+CREATE INDEX idx_tracks_lyrics_status ON tracks(lyrics_status);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_tracks_lyrics_status;
+ALTER TABLE tracks DROP COLUMN lyrics_status;
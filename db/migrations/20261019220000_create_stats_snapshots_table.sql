@@ -0,0 +1,16 @@
+-- migrate:up
+CREATE TABLE stats_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    day TEXT NOT NULL,
+    library TEXT NOT NULL,
+    total_tracks INTEGER NOT NULL,
+    tracks_with_synced_lyrics INTEGER NOT NULL,
+    tracks_with_plain_lyrics INTEGER NOT NULL,
+    instrumental_tracks INTEGER NOT NULL,
+    tracks_missing_metadata INTEGER NOT NULL,
+    recorded_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (day, library)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS stats_snapshots;
@@ -0,0 +1,34 @@
+-- migrate:up requires:fts5
+-- The index stores no content of its own, it reads it from the lyrics table.
+CREATE VIRTUAL TABLE lyrics_fts USING fts5(
+    plain_lyrics,
+    synced_lyrics,
+    content = 'lyrics',
+    content_rowid = 'track_id',
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER trg_lyrics_fts_insert AFTER INSERT ON lyrics
+BEGIN
+    INSERT INTO lyrics_fts (rowid, plain_lyrics, synced_lyrics) VALUES (NEW.track_id, NEW.plain_lyrics, NEW.synced_lyrics);
+END;
+
+CREATE TRIGGER trg_lyrics_fts_delete AFTER DELETE ON lyrics
+BEGIN
+    INSERT INTO lyrics_fts (lyrics_fts, rowid, plain_lyrics, synced_lyrics) VALUES ('delete', OLD.track_id, OLD.plain_lyrics, OLD.synced_lyrics);
+END;
+
+CREATE TRIGGER trg_lyrics_fts_update AFTER UPDATE ON lyrics
+BEGIN
+    INSERT INTO lyrics_fts (lyrics_fts, rowid, plain_lyrics, synced_lyrics) VALUES ('delete', OLD.track_id, OLD.plain_lyrics, OLD.synced_lyrics);
+    INSERT INTO lyrics_fts (rowid, plain_lyrics, synced_lyrics) VALUES (NEW.track_id, NEW.plain_lyrics, NEW.synced_lyrics);
+END;
+
+-- Index the lyrics stored before the index existed
+INSERT INTO lyrics_fts (lyrics_fts) VALUES ('rebuild');
+
+-- migrate:down
+DROP TRIGGER IF EXISTS trg_lyrics_fts_update;
+DROP TRIGGER IF EXISTS trg_lyrics_fts_delete;
+DROP TRIGGER IF EXISTS trg_lyrics_fts_insert;
+DROP TABLE IF EXISTS lyrics_fts;
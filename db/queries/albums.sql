@@ -0,0 +1,56 @@
+-- name: GetAlbums :many
+SELECT
+    al.id,
+    al.title,
+    CAST(COALESCE(al.artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(ar.name, '') AS TEXT) AS artist,
+    CAST(COALESCE(al.musicbrainz_id, '') AS TEXT) AS musicbrainz_id,
+    CAST(COALESCE(al.year, 0) AS INTEGER) AS year,
+    CAST(COUNT(t.id) AS INTEGER) AS track_count,
+    CAST(COALESCE(SUM(t.has_synced_lyrics), 0) AS INTEGER) AS synced_lyrics_count,
+    CAST(COALESCE(SUM(t.has_plain_lyrics), 0) AS INTEGER) AS plain_lyrics_count,
+    CAST(COALESCE(SUM(t.instrumental), 0) AS INTEGER) AS instrumental_count,
+    CAST(COALESCE(SUM(NOT t.has_synced_lyrics AND NOT t.has_plain_lyrics AND NOT t.instrumental), 0) AS INTEGER) AS tracks_without_lyrics
+FROM albums al
+LEFT JOIN artists ar ON ar.id = al.artist_id
+LEFT JOIN tracks t ON t.album_id = al.id
+GROUP BY al.id
+ORDER BY ar.name COLLATE NOCASE, al.year, al.title COLLATE NOCASE;
+
+-- name: GetAlbumByID :one
+SELECT
+    al.id,
+    al.title,
+    CAST(COALESCE(al.artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(ar.name, '') AS TEXT) AS artist,
+    CAST(COALESCE(al.musicbrainz_id, '') AS TEXT) AS musicbrainz_id,
+    CAST(COALESCE(al.year, 0) AS INTEGER) AS year
+FROM albums al
+LEFT JOIN artists ar ON ar.id = al.artist_id
+WHERE al.id = ?;
+
+-- name: GetAlbumByMusicBrainzID :one
+SELECT id, title, artist_id, musicbrainz_id, year
+FROM albums
+WHERE musicbrainz_id = ?;
+
+-- name: GetAlbumByTitleAndArtist :one
+SELECT id, title, artist_id, musicbrainz_id, year
+FROM albums
+WHERE title = ? COLLATE NOCASE AND artist_id IS ?
+ORDER BY id
+LIMIT 1;
+
+-- name: CreateAlbum :one
+INSERT INTO albums (
+    title,
+    artist_id,
+    musicbrainz_id,
+    year
+) VALUES (?, ?, ?, ?)
+RETURNING id;
+
+-- name: UpdateAlbum :exec
+UPDATE albums
+SET musicbrainz_id = ?, year = ?
+WHERE id = ?;
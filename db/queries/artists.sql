@@ -0,0 +1,33 @@
+-- name: GetArtists :many
+SELECT
+    a.id,
+    a.name,
+    CAST(COALESCE(a.musicbrainz_id, '') AS TEXT) AS musicbrainz_id,
+    CAST((SELECT COUNT(*) FROM albums WHERE albums.artist_id = a.id) AS INTEGER) AS album_count,
+    CAST((SELECT COUNT(*) FROM tracks WHERE tracks.artist_id = a.id) AS INTEGER) AS track_count
+FROM artists a
+ORDER BY a.name COLLATE NOCASE, a.id;
+
+-- name: GetArtistByMusicBrainzID :one
+SELECT id, name, musicbrainz_id
+FROM artists
+WHERE musicbrainz_id = ?;
+
+-- name: GetArtistByName :one
+SELECT id, name, musicbrainz_id
+FROM artists
+WHERE name = ? COLLATE NOCASE
+ORDER BY id
+LIMIT 1;
+
+-- name: CreateArtist :one
+INSERT INTO artists (
+    name,
+    musicbrainz_id
+) VALUES (?, ?)
+RETURNING id;
+
+-- name: UpdateArtistMusicBrainzID :exec
+UPDATE artists
+SET musicbrainz_id = ?
+WHERE id = ?;
@@ -0,0 +1,35 @@
+-- name: GetLyricsByTrackID :one
+SELECT
+    track_id,
+    CAST(COALESCE(plain_lyrics, '') AS TEXT) AS plain_lyrics,
+    CAST(COALESCE(synced_lyrics, '') AS TEXT) AS synced_lyrics,
+    provider,
+    CAST(COALESCE(provider_id, 0) AS INTEGER) AS provider_id,
+    fetched_at,
+    content_hash,
+    edited
+FROM lyrics
+WHERE track_id = ?;
+
+-- name: UpsertLyrics :exec
+INSERT INTO lyrics (
+    track_id,
+    plain_lyrics,
+    synced_lyrics,
+    provider,
+    provider_id,
+    fetched_at,
+    content_hash,
+    edited
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (track_id) DO UPDATE SET
+    plain_lyrics = excluded.plain_lyrics,
+    synced_lyrics = excluded.synced_lyrics,
+    provider = excluded.provider,
+    provider_id = excluded.provider_id,
+    fetched_at = excluded.fetched_at,
+    content_hash = excluded.content_hash,
+    edited = excluded.edited;
+
+-- name: DeleteLyrics :exec
+DELETE FROM lyrics WHERE track_id = ?;
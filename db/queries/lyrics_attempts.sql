@@ -0,0 +1,30 @@
+-- name: CreateLyricsAttempt :exec
+INSERT INTO lyrics_attempts (
+    track_id,
+    attempted_at,
+    provider,
+    track_name,
+    artist_name,
+    album_name,
+    duration,
+    outcome,
+    error,
+    provider_id
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetLyricsAttemptsByTrackID :many
+SELECT
+    id,
+    track_id,
+    attempted_at,
+    provider,
+    CAST(COALESCE(track_name, '') AS TEXT) AS track_name,
+    CAST(COALESCE(artist_name, '') AS TEXT) AS artist_name,
+    CAST(COALESCE(album_name, '') AS TEXT) AS album_name,
+    CAST(COALESCE(duration, 0) AS INTEGER) AS duration,
+    outcome,
+    CAST(COALESCE(error, '') AS TEXT) AS error,
+    CAST(COALESCE(provider_id, 0) AS INTEGER) AS provider_id
+FROM lyrics_attempts
+WHERE track_id = ?
+ORDER BY attempted_at DESC, id DESC;
@@ -0,0 +1,49 @@
+-- name: GetCoverageStats :one
+SELECT
+    COUNT(*) AS total_tracks,
+    CAST(COALESCE(SUM(CASE WHEN has_synced_lyrics = 1 THEN 1 ELSE 0 END), 0) AS INTEGER) AS tracks_with_synced_lyrics,
+    CAST(COALESCE(SUM(CASE WHEN has_plain_lyrics = 1 THEN 1 ELSE 0 END), 0) AS INTEGER) AS tracks_with_plain_lyrics,
+    CAST(COALESCE(SUM(CASE WHEN instrumental = 1 THEN 1 ELSE 0 END), 0) AS INTEGER) AS instrumental_tracks,
+    CAST(COALESCE(SUM(
+        CASE WHEN title IS NULL OR title = ''
+              OR artist IS NULL OR artist = ''
+              OR album IS NULL OR album = ''
+        THEN 1 ELSE 0 END
+    ), 0) AS INTEGER) AS tracks_missing_metadata
+FROM tracks
+WHERE path LIKE CAST(sqlc.arg(pattern) AS TEXT) ESCAPE '\';
+
+-- name: UpsertStatsSnapshot :exec
+INSERT INTO stats_snapshots (
+    day,
+    library,
+    total_tracks,
+    tracks_with_synced_lyrics,
+    tracks_with_plain_lyrics,
+    instrumental_tracks,
+    tracks_missing_metadata,
+    recorded_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (day, library) DO UPDATE SET
+    total_tracks = excluded.total_tracks,
+    tracks_with_synced_lyrics = excluded.tracks_with_synced_lyrics,
+    tracks_with_plain_lyrics = excluded.tracks_with_plain_lyrics,
+    instrumental_tracks = excluded.instrumental_tracks,
+    tracks_missing_metadata = excluded.tracks_missing_metadata,
+    recorded_at = excluded.recorded_at;
+
+-- name: GetStatsSnapshots :many
+SELECT
+    day,
+    library,
+    total_tracks,
+    tracks_with_synced_lyrics,
+    tracks_with_plain_lyrics,
+    instrumental_tracks,
+    tracks_missing_metadata,
+    recorded_at
+FROM stats_snapshots
+WHERE library = CAST(sqlc.arg(library) AS TEXT)
+  AND day >= CAST(sqlc.arg(from_day) AS TEXT)
+  AND day <= CAST(sqlc.arg(to_day) AS TEXT)
+ORDER BY day;
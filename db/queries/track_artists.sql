@@ -0,0 +1,15 @@
+-- name: GetTrackArtists :many
+SELECT artist
+FROM track_artists
+WHERE track_id = ?
+ORDER BY position;
+
+-- name: CreateTrackArtist :exec
+INSERT INTO track_artists (
+    track_id,
+    position,
+    artist
+) VALUES (?, ?, ?);
+
+-- name: DeleteTrackArtists :exec
+DELETE FROM track_artists WHERE track_id = ?;
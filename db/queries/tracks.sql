@@ -2,24 +2,155 @@
 SELECT
     id,
     path,
-    CAST(title AS TEXT),
-    CAST(artist AS TEXT),
-    CAST(album AS TEXT),
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
     duration,
     has_plain_lyrics,
-    has_synced_lyrics
-FROM tracks;
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
+FROM tracks
+ORDER BY artist, album, disc_number, track_number, path;
+
+-- name: GetTracksByArtist :many
+SELECT
+    id,
+    path,
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
+    duration,
+    has_plain_lyrics,
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
+FROM tracks
+WHERE id IN (
+    SELECT track_id FROM track_artists WHERE track_artists.artist = CAST(sqlc.arg(artist) AS TEXT) COLLATE NOCASE
+)
+ORDER BY artist, album, disc_number, track_number, path;
+
+-- name: GetTracksByAlbumID :many
+SELECT
+    id,
+    path,
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
+    duration,
+    has_plain_lyrics,
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
+FROM tracks
+WHERE album_id = ?
+ORDER BY disc_number, track_number, path;
 
 -- name: GetTrackByPath :one
 SELECT
     id,
     path,
-    CAST(title AS TEXT),
-    CAST(artist AS TEXT),
-    CAST(album AS TEXT),
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
     duration,
     has_plain_lyrics,
-    has_synced_lyrics
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
 FROM tracks
 WHERE path = ?
 LIMIT 1;
@@ -28,12 +159,37 @@ LIMIT 1;
 SELECT
     id,
     path,
-    CAST(title AS TEXT),
-    CAST(artist AS TEXT),
-    CAST(album AS TEXT),
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
     duration,
     has_plain_lyrics,
-    has_synced_lyrics
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
 FROM tracks
 WHERE id = ?
 LIMIT 1;
@@ -43,39 +199,184 @@ INSERT INTO tracks (
     path,
     title,
     artist,
+    album_artist,
     album,
     duration,
     has_plain_lyrics,
-    has_synced_lyrics
-) VALUES (?, ?, ?, ?, ?, ?, ?);
+    has_synced_lyrics,
+    track_number,
+    disc_number,
+    release_date,
+    year,
+    genre,
+    composer,
+    musicbrainz_recording_id,
+    musicbrainz_release_id,
+    isrc,
+    format,
+    bitrate,
+    sample_rate,
+    channels,
+    file_size,
+    audio_path,
+    cue_sheet_path,
+    start_offset
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: UpdateTrack :exec
 UPDATE tracks
 SET
     title = ?,
     artist = ?,
+    album_artist = ?,
     album = ?,
     duration = ?,
     has_plain_lyrics = ?,
-    has_synced_lyrics = ?
+    has_synced_lyrics = ?,
+    track_number = ?,
+    disc_number = ?,
+    release_date = ?,
+    year = ?,
+    genre = ?,
+    composer = ?,
+    musicbrainz_recording_id = ?,
+    musicbrainz_release_id = ?,
+    isrc = ?,
+    format = ?,
+    bitrate = ?,
+    sample_rate = ?,
+    channels = ?,
+    file_size = ?,
+    audio_path = ?,
+    cue_sheet_path = ?,
+    start_offset = ?
 WHERE path = ?;
 
+-- name: GetTracksByAlbum :many
+SELECT
+    id,
+    path,
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
+    duration,
+    has_plain_lyrics,
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
+FROM tracks
+WHERE album = sqlc.arg(album)
+  AND COALESCE(album_artist, artist, '') = CAST(sqlc.arg(album_artist) AS TEXT)
+ORDER BY disc_number, track_number, path;
+
+-- name: UpdateTrackEntities :exec
+UPDATE tracks
+SET artist_id = ?, album_id = ?
+WHERE id = ?;
+
+-- name: UpdateTrackLyricsOffset :exec
+UPDATE tracks
+SET lyrics_offset = ?
+WHERE id = ?;
+
+-- name: UpdateTrackLyricsIssue :exec
+UPDATE tracks
+SET lyrics_issue = ?
+WHERE path = ?;
+
+-- name: UpdateTrackLyricsFlags :exec
+UPDATE tracks
+SET has_plain_lyrics = ?, has_synced_lyrics = ?
+WHERE id = ?;
+
+-- name: UpdateTrackInstrumental :exec
+UPDATE tracks
+SET instrumental = ?, instrumental_source = ?
+WHERE id = ?;
+
+-- name: UpdateTrackInstrumentalByPath :exec
+UPDATE tracks
+SET instrumental = ?, instrumental_source = ?
+WHERE path = ?;
+
+-- name: UpdateTrackLyricsStatus :exec
+UPDATE tracks SET lyrics_status = ? WHERE id = ?;
+
+-- name: ListTrackPathsWithoutEntities :many
+SELECT path FROM tracks
+WHERE (artist_id IS NULL AND COALESCE(artist, '') != '')
+   OR (album_id IS NULL AND COALESCE(album, '') != '');
+
+-- name: ResetSearchingLyricsStatuses :execrows
+UPDATE tracks SET lyrics_status = 'pending' WHERE lyrics_status = 'searching';
+
 -- name: DeleteTrack :exec
 DELETE FROM tracks WHERE path = ?;
 
+-- name: DeleteCueSheetTracks :exec
+DELETE FROM tracks WHERE audio_path = ? OR cue_sheet_path = ?;
+
 -- name: SearchTracks :many
 SELECT
     id,
     path,
-    CAST(title AS TEXT),
-    CAST(artist AS TEXT),
-    CAST(album AS TEXT),
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
     duration,
     has_plain_lyrics,
-    has_synced_lyrics
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
 FROM tracks
 WHERE title LIKE ? OR artist LIKE ? OR album LIKE ?
-ORDER BY artist, album, title;
+ORDER BY artist, album, disc_number, track_number, title;
 
 -- name: GetStats :one
 SELECT
@@ -83,11 +384,34 @@ SELECT
     CAST(SUM(CASE WHEN has_plain_lyrics = 1 AND has_synced_lyrics = 1 THEN 1 ELSE 0 END) AS INTEGER) AS tracks_with_both_lyrics,
     CAST(SUM(CASE WHEN has_plain_lyrics = 0 AND has_synced_lyrics = 1 THEN 1 ELSE 0 END) AS INTEGER) AS tracks_with_synced_only,
     CAST(SUM(CASE WHEN has_plain_lyrics = 1 AND has_synced_lyrics = 0 THEN 1 ELSE 0 END) AS INTEGER) AS tracks_with_plain_only,
-    CAST(SUM(CASE WHEN has_plain_lyrics = 0 AND has_synced_lyrics = 0 THEN 1 ELSE 0 END) AS INTEGER) AS instrumental_tracks,
+    CAST(SUM(CASE WHEN instrumental = 1 THEN 1 ELSE 0 END) AS INTEGER) AS instrumental_tracks,
+    CAST(SUM(CASE WHEN has_plain_lyrics = 0 AND has_synced_lyrics = 0 AND instrumental = 0 THEN 1 ELSE 0 END) AS INTEGER) AS tracks_without_lyrics,
     CAST(SUM(
         CASE WHEN title IS NULL OR title = ''
               OR artist IS NULL OR artist = ''
               OR album IS NULL OR album = ''
         THEN 1 ELSE 0 END
-    ) AS INTEGER) AS tracks_missing_metadata
+    ) AS INTEGER) AS tracks_missing_metadata,
+    CAST(COALESCE(SUM(file_size), 0) AS INTEGER) AS total_file_size,
+    CAST(COALESCE(AVG(bitrate), 0) AS INTEGER) AS average_bitrate,
+    CAST(SUM(CASE WHEN lyrics_status = 'pending' THEN 1 ELSE 0 END) AS INTEGER) AS status_pending,
+    CAST(SUM(CASE WHEN lyrics_status = 'searching' THEN 1 ELSE 0 END) AS INTEGER) AS status_searching,
+    CAST(SUM(CASE WHEN lyrics_status = 'found' THEN 1 ELSE 0 END) AS INTEGER) AS status_found,
+    CAST(SUM(CASE WHEN lyrics_status = 'partial' THEN 1 ELSE 0 END) AS INTEGER) AS status_partial,
+    CAST(SUM(CASE WHEN lyrics_status = 'not_found' THEN 1 ELSE 0 END) AS INTEGER) AS status_not_found,
+    CAST(SUM(CASE WHEN lyrics_status = 'instrumental' THEN 1 ELSE 0 END) AS INTEGER) AS status_instrumental,
+    CAST(SUM(CASE WHEN lyrics_status = 'manual' THEN 1 ELSE 0 END) AS INTEGER) AS status_manual,
+    CAST(SUM(CASE WHEN lyrics_status = 'ignored' THEN 1 ELSE 0 END) AS INTEGER) AS status_ignored,
+    CAST(SUM(CASE WHEN lyrics_status = 'error' THEN 1 ELSE 0 END) AS INTEGER) AS status_error
 FROM tracks;
+
+-- name: GetFormatStats :many
+SELECT
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    COUNT(*) AS total_tracks,
+    CAST(COALESCE(SUM(file_size), 0) AS INTEGER) AS total_file_size,
+    CAST(COALESCE(AVG(bitrate), 0) AS INTEGER) AS average_bitrate,
+    CAST(COALESCE(AVG(sample_rate), 0) AS INTEGER) AS average_sample_rate
+FROM tracks
+GROUP BY format
+ORDER BY total_tracks DESC, format;
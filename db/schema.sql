@@ -32,11 +32,149 @@ CREATE TABLE tracks (
     duration REAL NOT NULL,
     has_plain_lyrics BOOLEAN NOT NULL DEFAULT 0,
     has_synced_lyrics BOOLEAN NOT NULL DEFAULT 0
-);
+, lyrics_offset INTEGER NOT NULL DEFAULT 0, lyrics_issue TEXT, track_number INTEGER, disc_number INTEGER, release_date TEXT, year INTEGER, genre TEXT, composer TEXT, musicbrainz_recording_id TEXT, musicbrainz_release_id TEXT, isrc TEXT, album_artist TEXT, format TEXT, bitrate INTEGER, sample_rate INTEGER, channels INTEGER, file_size INTEGER, audio_path TEXT, cue_sheet_path TEXT, start_offset REAL NOT NULL DEFAULT 0, instrumental BOOLEAN NOT NULL DEFAULT 0, instrumental_source TEXT, artist_id INTEGER, album_id INTEGER, lyrics_status TEXT NOT NULL DEFAULT 'pending');
 CREATE INDEX idx_tracks_title ON tracks(title);
 CREATE INDEX idx_tracks_artist ON tracks(artist);
 CREATE INDEX idx_tracks_album ON tracks(album);
 CREATE INDEX idx_tracks_path ON tracks(path);
+CREATE INDEX idx_tracks_musicbrainz_recording_id ON tracks(musicbrainz_recording_id);
+CREATE INDEX idx_tracks_musicbrainz_release_id ON tracks(musicbrainz_release_id);
+CREATE INDEX idx_tracks_isrc ON tracks(isrc);
+CREATE INDEX idx_tracks_album_artist ON tracks(album_artist);
+CREATE INDEX idx_tracks_format ON tracks(format);
+CREATE INDEX idx_tracks_audio_path ON tracks(audio_path);
+CREATE INDEX idx_tracks_cue_sheet_path ON tracks(cue_sheet_path);
+CREATE TABLE track_artists (
+    track_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    artist TEXT NOT NULL,
+    PRIMARY KEY (track_id, position),
+    FOREIGN KEY (track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_track_artists_artist ON track_artists(artist COLLATE NOCASE);
+CREATE TRIGGER trg_tracks_delete_artists AFTER DELETE ON tracks
+BEGIN
+    DELETE FROM track_artists WHERE track_id = OLD.id;
+END;
+CREATE TABLE lyrics (
+    track_id INTEGER PRIMARY KEY,
+    plain_lyrics TEXT,
+    synced_lyrics TEXT,
+    provider TEXT NOT NULL,
+    provider_id INTEGER,
+    fetched_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    content_hash TEXT NOT NULL,
+    edited BOOLEAN NOT NULL DEFAULT 0,
+    FOREIGN KEY (track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+CREATE TRIGGER trg_tracks_delete_lyrics AFTER DELETE ON tracks
+BEGIN
+    DELETE FROM lyrics WHERE track_id = OLD.id;
+END;
+CREATE TABLE artists (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    musicbrainz_id TEXT UNIQUE
+);
+CREATE INDEX idx_artists_name ON artists(name COLLATE NOCASE);
+CREATE TABLE albums (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    artist_id INTEGER,
+    musicbrainz_id TEXT UNIQUE,
+    year INTEGER,
+    FOREIGN KEY (artist_id) REFERENCES artists(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_albums_title ON albums(title COLLATE NOCASE);
+CREATE INDEX idx_albums_artist_id ON albums(artist_id);
+CREATE INDEX idx_tracks_artist_id ON tracks(artist_id);
+CREATE INDEX idx_tracks_album_id ON tracks(album_id);
+CREATE TRIGGER trg_tracks_delete_albums AFTER DELETE ON tracks
+BEGIN
+    DELETE FROM albums WHERE id = OLD.album_id AND NOT EXISTS (SELECT 1 FROM tracks WHERE album_id = OLD.album_id);
+    DELETE FROM artists WHERE id = OLD.artist_id
+        AND NOT EXISTS (SELECT 1 FROM tracks WHERE artist_id = OLD.artist_id)
+        AND NOT EXISTS (SELECT 1 FROM albums WHERE artist_id = OLD.artist_id);
+END;
+CREATE TRIGGER trg_tracks_update_albums AFTER UPDATE OF artist_id, album_id ON tracks
+BEGIN
+    DELETE FROM albums WHERE id = OLD.album_id AND NOT EXISTS (SELECT 1 FROM tracks WHERE album_id = OLD.album_id);
+    DELETE FROM artists WHERE id = OLD.artist_id
+        AND NOT EXISTS (SELECT 1 FROM tracks WHERE artist_id = OLD.artist_id)
+        AND NOT EXISTS (SELECT 1 FROM albums WHERE artist_id = OLD.artist_id);
+END;
+CREATE TRIGGER trg_albums_delete_artists AFTER DELETE ON albums
+BEGIN
+    DELETE FROM artists WHERE id = OLD.artist_id
+        AND NOT EXISTS (SELECT 1 FROM tracks WHERE artist_id = OLD.artist_id)
+        AND NOT EXISTS (SELECT 1 FROM albums WHERE artist_id = OLD.artist_id);
+END;
+CREATE TABLE lyrics_attempts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    track_id INTEGER NOT NULL,
+    attempted_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    provider TEXT NOT NULL,
+    track_name TEXT,
+    artist_name TEXT,
+    album_name TEXT,
+    duration INTEGER,
+    outcome TEXT NOT NULL,
+    error TEXT,
+    provider_id INTEGER,
+    FOREIGN KEY (track_id) REFERENCES tracks(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_lyrics_attempts_track_id ON lyrics_attempts(track_id, attempted_at);
+CREATE TRIGGER trg_tracks_delete_lyrics_attempts AFTER DELETE ON tracks
+BEGIN
+    DELETE FROM lyrics_attempts WHERE track_id = OLD.id;
+END;
+CREATE INDEX idx_tracks_lyrics_status ON tracks(lyrics_status);
+CREATE TABLE stats_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    day TEXT NOT NULL,
+    library TEXT NOT NULL,
+    total_tracks INTEGER NOT NULL,
+    tracks_with_synced_lyrics INTEGER NOT NULL,
+    tracks_with_plain_lyrics INTEGER NOT NULL,
+    instrumental_tracks INTEGER NOT NULL,
+    tracks_missing_metadata INTEGER NOT NULL,
+    recorded_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (day, library)
+);
+CREATE VIRTUAL TABLE lyrics_fts USING fts5(
+    plain_lyrics,
+    synced_lyrics,
+    content = 'lyrics',
+    content_rowid = 'track_id',
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+CREATE TRIGGER trg_lyrics_fts_insert AFTER INSERT ON lyrics
+BEGIN
+    INSERT INTO lyrics_fts (rowid, plain_lyrics, synced_lyrics) VALUES (NEW.track_id, NEW.plain_lyrics, NEW.synced_lyrics);
+END;
+CREATE TRIGGER trg_lyrics_fts_delete AFTER DELETE ON lyrics
+BEGIN
+    INSERT INTO lyrics_fts (lyrics_fts, rowid, plain_lyrics, synced_lyrics) VALUES ('delete', OLD.track_id, OLD.plain_lyrics, OLD.synced_lyrics);
+END;
+CREATE TRIGGER trg_lyrics_fts_update AFTER UPDATE ON lyrics
+BEGIN
+    INSERT INTO lyrics_fts (lyrics_fts, rowid, plain_lyrics, synced_lyrics) VALUES ('delete', OLD.track_id, OLD.plain_lyrics, OLD.synced_lyrics);
+    INSERT INTO lyrics_fts (rowid, plain_lyrics, synced_lyrics) VALUES (NEW.track_id, NEW.plain_lyrics, NEW.synced_lyrics);
+END;
 -- Dbmate schema migrations
 INSERT INTO "schema_migrations" (version) VALUES
-  (20251116073135);
+  (20251116073135),
+  (20261019100000),
+  (20261019110000),
+  (20261019120000),
+  (20261019130000),
+  (20261019140000),
+  (20261019150000),
+  (20261019160000),
+  (20261019170000),
+  (20261019180000),
+  (20261019190000),
+  (20261019200000),
+  (20261019210000),
+  (20261019220000),
+  (20261019230000);
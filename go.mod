@@ -12,6 +12,8 @@ require (
 	github.com/onsi/gomega v1.38.2
 	github.com/spf13/viper v1.21.0
 	go.senan.xyz/taglib v0.10.4
+	golang.org/x/image v0.25.0
+	golang.org/x/text v0.31.0
 )
 
 require (
@@ -45,7 +47,6 @@ require (
 	golang.org/x/net v0.47.0 // indirect
 	golang.org/x/sync v0.18.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
-	golang.org/x/text v0.31.0 // indirect
 	golang.org/x/tools v0.39.0 // indirect
 	google.golang.org/protobuf v1.36.10 // indirect
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
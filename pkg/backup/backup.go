@@ -0,0 +1,179 @@
+// Package backup copies the SQLite database with the online backup API, which takes a consistent
+// snapshot while the application keeps reading and writing it.
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	// FILE_PREFIX starts the name of every scheduled backup.
+	FILE_PREFIX = "refrain-"
+	// FILE_EXTENSION ends the name of every backup.
+	FILE_EXTENSION = ".db"
+	// timestampLayout orders the backups by name from the oldest to the most recent.
+	timestampLayout = "20060102T150405Z"
+	// stepRetryDelay is how long to wait before retrying a step blocked by a writer.
+	stepRetryDelay = 50 * time.Millisecond
+)
+
+var (
+	ErrNotSQLite = errors.New("backups are only supported for sqlite3 databases")
+	// ErrCorruptBackup is returned when a backup to restore fails the integrity check.
+	ErrCorruptBackup = errors.New("backup failed the integrity check")
+)
+
+// FileName returns the name of a backup taken at t.
+func FileName(t time.Time) string {
+	return FILE_PREFIX + t.UTC().Format(timestampLayout) + FILE_EXTENSION
+}
+
+// Backup copies the database to dest. The copy is written next to dest then renamed, so dest is
+// either the previous file or a complete backup.
+func Backup(ctx context.Context, db *sql.DB, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	tmp := dest + ".tmp"
+	defer os.Remove(tmp)
+
+	target, err := sql.Open("sqlite3", tmp)
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	if err := copyDatabase(ctx, db, target); err != nil {
+		return err
+	}
+	if err := target.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, dest)
+}
+
+// Restore replaces the content of the database with the backup at src, after checking the backup
+// is a sound SQLite database and passes validate, when given, which reads it without modifying it.
+// Other connections see the restored content once it is complete.
+func Restore(ctx context.Context, db *sql.DB, src string, validate func(context.Context, *sql.DB) error) error {
+	if _, err := os.Stat(src); err != nil {
+		return err
+	}
+
+	source, err := sql.Open("sqlite3", "file:"+src+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	var result string
+	if err := source.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorruptBackup, err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("%w: %s", ErrCorruptBackup, result)
+	}
+
+	if validate != nil {
+		if err := validate(ctx, source); err != nil {
+			return err
+		}
+	}
+
+	return copyDatabase(ctx, source, db)
+}
+
+// Prune removes the oldest backups of dir, keeping the given number of the most recent ones.
+// Only the files named by FileName are considered.
+func Prune(dir string, keep int) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, FILE_PREFIX) && strings.HasSuffix(name, FILE_EXTENSION) {
+			backups = append(backups, name)
+		}
+	}
+	slices.Sort(backups)
+
+	removed := []string{}
+	for len(backups) > max(keep, 0) {
+		p := filepath.Join(dir, backups[0])
+		if err := os.Remove(p); err != nil {
+			return removed, err
+		}
+		removed = append(removed, p)
+		backups = backups[1:]
+	}
+
+	return removed, nil
+}
+
+// copyDatabase copies every page of the main database of src to the main database of dest.
+func copyDatabase(ctx context.Context, src, dest *sql.DB) error {
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+
+	destConn, err := dest.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer destConn.Close()
+
+	return destConn.Raw(func(destDriverConn any) error {
+		return srcConn.Raw(func(srcDriverConn any) error {
+			destSQLite, ok := destDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return ErrNotSQLite
+			}
+			srcSQLite, ok := srcDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return ErrNotSQLite
+			}
+
+			b, err := destSQLite.Backup("main", srcSQLite, "main")
+			if err != nil {
+				return err
+			}
+
+			// Copying every page in one step reads a single snapshot, which under WAL never blocks writers.
+			// A step only fails to proceed while another connection holds a conflicting lock.
+			for {
+				done, err := b.Step(-1)
+				if err != nil {
+					b.Close()
+					return err
+				}
+				if done {
+					return b.Finish()
+				}
+
+				select {
+				case <-ctx.Done():
+					b.Close()
+					return ctx.Err()
+				case <-time.After(stepRetryDelay):
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,108 @@
+package backup_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/backup"
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup", func() {
+	var (
+		ctx      context.Context
+		dir      string
+		database *sql.DB
+	)
+
+	open := func(p string) *sql.DB {
+		db, err := sql.Open("sqlite3", p+"?_journal=WAL")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(db.Close)
+		return db
+	}
+
+	titles := func(db *sql.DB) []string {
+		rows, err := db.Query("SELECT title FROM tracks ORDER BY title")
+		Expect(err).ToNot(HaveOccurred())
+		defer rows.Close()
+
+		titles := []string{}
+		for rows.Next() {
+			var title string
+			Expect(rows.Scan(&title)).To(Succeed())
+			titles = append(titles, title)
+		}
+		return titles
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		dir = GinkgoT().TempDir()
+		database = open(filepath.Join(dir, "refrain.db"))
+
+		_, err := database.Exec("CREATE TABLE tracks (title TEXT); INSERT INTO tracks (title) VALUES ('Vore'), ('Granite');")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should copy the database while it is open", func() {
+		p := filepath.Join(dir, "backups", backup.FileName(time.Now()))
+		Expect(backup.Backup(ctx, database, p)).To(Succeed())
+
+		_, err := database.Exec("INSERT INTO tracks (title) VALUES ('Chokehold')")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(titles(open(p))).To(Equal([]string{"Granite", "Vore"}))
+		Expect(p + ".tmp").ToNot(BeAnExistingFile())
+	})
+
+	It("should restore a backup into the database", func() {
+		p := filepath.Join(dir, "snapshot.db")
+		Expect(backup.Backup(ctx, database, p)).To(Succeed())
+
+		_, err := database.Exec("DELETE FROM tracks")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(backup.Restore(ctx, database, p, nil)).To(Succeed())
+		Expect(titles(database)).To(Equal([]string{"Granite", "Vore"}))
+	})
+
+	It("should not restore backups failing validation", func() {
+		p := filepath.Join(dir, "snapshot.db")
+		Expect(backup.Backup(ctx, database, p)).To(Succeed())
+
+		_, err := database.Exec("DELETE FROM tracks")
+		Expect(err).ToNot(HaveOccurred())
+
+		rejected := os.ErrInvalid
+		Expect(backup.Restore(ctx, database, p, func(context.Context, *sql.DB) error {
+			return rejected
+		})).To(MatchError(rejected))
+		Expect(titles(database)).To(BeEmpty())
+
+		garbage := filepath.Join(dir, "garbage.db")
+		Expect(os.WriteFile(garbage, []byte("not a database at all, only some text"), 0644)).To(Succeed())
+		Expect(backup.Restore(ctx, database, garbage, nil)).To(MatchError(backup.ErrCorruptBackup))
+	})
+
+	It("should keep the most recent backups only", func() {
+		start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+		for i := range 4 {
+			Expect(os.WriteFile(filepath.Join(dir, backup.FileName(start.Add(time.Duration(i)*time.Hour))), nil, 0644)).To(Succeed())
+		}
+
+		removed, err := backup.Prune(dir, 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(Equal([]string{
+			filepath.Join(dir, "refrain-20261019T120000Z.db"),
+			filepath.Join(dir, "refrain-20261019T130000Z.db"),
+		}))
+		Expect(filepath.Join(dir, "refrain-20261019T150000Z.db")).To(BeAnExistingFile())
+		Expect(filepath.Join(dir, "refrain.db")).To(BeAnExistingFile())
+	})
+})
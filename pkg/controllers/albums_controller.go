@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/gerald-lbn/refrain/pkg/repository"
+	"github.com/gerald-lbn/refrain/pkg/services"
+	dbUtils "github.com/gerald-lbn/refrain/pkg/utils/db"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AlbumsController struct {
+	container *services.Container
+}
+
+func NewAlbumsController(container *services.Container) *AlbumsController {
+	return &AlbumsController{
+		container: container,
+	}
+}
+
+// Index returns every album with how many of its tracks have lyrics.
+func (c *AlbumsController) Index(ctx *fiber.Ctx) error {
+	repo := repository.New(c.container.Database)
+	albums, err := repo.GetAlbums(ctx.UserContext())
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.JSON(albums)
+}
+
+// albumTracksResponse lists the tracks of an album along with the album.
+type albumTracksResponse struct {
+	repository.GetAlbumByIDRow
+	Tracks []repository.GetTracksByAlbumIDRow `json:"tracks"`
+}
+
+// Tracks returns the tracks of an album in disc and track order.
+func (c *AlbumsController) Tracks(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	intId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid ID",
+		})
+	}
+
+	repo := repository.New(c.container.Database)
+	album, err := repo.GetAlbumByID(ctx.UserContext(), intId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "album not found",
+			})
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	tracks, err := repo.GetTracksByAlbumID(ctx.UserContext(), dbUtils.Int64ToNullInt64(album.ID))
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(albumTracksResponse{GetAlbumByIDRow: album, Tracks: tracks})
+}
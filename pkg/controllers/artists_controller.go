@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"github.com/gerald-lbn/refrain/pkg/repository"
+	"github.com/gerald-lbn/refrain/pkg/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ArtistsController struct {
+	container *services.Container
+}
+
+func NewArtistsController(container *services.Container) *ArtistsController {
+	return &ArtistsController{
+		container: container,
+	}
+}
+
+// Index returns every artist with the number of their albums and tracks.
+func (c *ArtistsController) Index(ctx *fiber.Ctx) error {
+	repo := repository.New(c.container.Database)
+	artists, err := repo.GetArtists(ctx.UserContext())
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.JSON(artists)
+}
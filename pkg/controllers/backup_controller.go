@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/backup"
+	"github.com/gerald-lbn/refrain/pkg/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+type BackupController struct {
+	container *services.Container
+}
+
+func NewBackupController(container *services.Container) *BackupController {
+	return &BackupController{
+		container: container,
+	}
+}
+
+// Download returns a consistent snapshot of the database, taken while the application keeps running.
+// It is only routed when database.backup.download is enabled.
+func (c *BackupController) Download(ctx *fiber.Ctx) error {
+	dir, err := os.MkdirTemp("", "refrain-backup-")
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	defer os.RemoveAll(dir)
+
+	name := backup.FileName(time.Now())
+	p := filepath.Join(dir, name)
+	if err := backup.Backup(ctx.UserContext(), c.container.Database, p); err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// The open file stays readable once its directory is removed, and is closed once sent
+	f, err := os.Open(p)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx.Attachment(name)
+	ctx.Set(fiber.HeaderContentType, "application/vnd.sqlite3")
+	return ctx.SendStream(f, int(info.Size()))
+}
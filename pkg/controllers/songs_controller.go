@@ -5,9 +5,14 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/gerald-lbn/refrain/pkg/library"
+	"github.com/gerald-lbn/refrain/pkg/lyrics"
+	"github.com/gerald-lbn/refrain/pkg/music"
 	"github.com/gerald-lbn/refrain/pkg/repository"
 	"github.com/gerald-lbn/refrain/pkg/services"
+	"github.com/gerald-lbn/refrain/pkg/tasks"
 	dbUtils "github.com/gerald-lbn/refrain/pkg/utils/db"
 	"github.com/gofiber/fiber/v2"
 )
@@ -22,14 +27,59 @@ func NewSongsController(container *services.Container) *SongsController {
 	}
 }
 
-// Index returns a list of all songs.
+// Index returns a page of the songs matching the filters given in the query, along with how many
+// songs match.
 func (c *SongsController) Index(ctx *fiber.Ctx) error {
-	repo := repository.New(c.container.Database)
-	songs, err := repo.GetAllTracks(ctx.UserContext())
+	opts := library.ListOptions{
+		Sort:       ctx.Query("sort"),
+		Descending: strings.EqualFold(ctx.Query("order"), "desc"),
+		Limit:      ctx.QueryInt("limit"),
+		Offset:     ctx.QueryInt("offset"),
+		Cursor:     ctx.Query("cursor"),
+		TrackFilters: library.TrackFilters{
+			Artist:     ctx.Query("artist"),
+			Album:      ctx.Query("album"),
+			PathPrefix: ctx.Query("path_prefix"),
+		},
+	}
+
+	for name, filter := range map[string]**bool{
+		"has_plain":        &opts.HasPlainLyrics,
+		"has_synced":       &opts.HasSyncedLyrics,
+		"instrumental":     &opts.Instrumental,
+		"missing_metadata": &opts.MissingMetadata,
+	} {
+		value := ctx.Query(name)
+		if value == "" {
+			continue
+		}
+
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid " + name,
+			})
+		}
+		*filter = &b
+	}
+
+	if statuses := ctx.Query("status"); statuses != "" {
+		for name := range strings.SplitSeq(statuses, ",") {
+			status, err := music.ParseLyricsStatus(strings.TrimSpace(name))
+			if err != nil {
+				return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
+			opts.Statuses = append(opts.Statuses, status)
+		}
+	}
+
+	page, err := library.ListTracks(ctx.UserContext(), c.container.Database, opts)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "no songs found",
+		if errors.Is(err, library.ErrInvalidSort) || errors.Is(err, library.ErrInvalidCursor) || errors.Is(err, library.ErrCursorWithOffset) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
 			})
 		}
 
@@ -37,7 +87,7 @@ func (c *SongsController) Index(ctx *fiber.Ctx) error {
 			"error": err.Error(),
 		})
 	}
-	return ctx.JSON(songs)
+	return ctx.JSON(page)
 }
 
 // Search returns a list of songs matching the given query.
@@ -63,6 +113,23 @@ func (c *SongsController) Search(ctx *fiber.Ctx) error {
 	return ctx.JSON(songs)
 }
 
+// SearchLyrics returns the songs whose lyrics contain the given query, with the matching line.
+func (c *SongsController) SearchLyrics(ctx *fiber.Ctx) error {
+	results, err := lyrics.Search(ctx.UserContext(), c.container.Database, ctx.Query("query"), ctx.QueryInt("limit"))
+	if err != nil {
+		if errors.Is(err, lyrics.ErrEmptyQuery) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.JSON(results)
+}
+
 // Show returns a single song by ID.
 func (c *SongsController) Show(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
@@ -86,5 +153,298 @@ func (c *SongsController) Show(ctx *fiber.Ctx) error {
 			"error": err.Error(),
 		})
 	}
+
+	artists, err := repo.GetTrackArtists(ctx.UserContext(), song.ID)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(songResponse{GetTrackByIDRow: song, Artists: artists})
+}
+
+// songResponse adds every artist performing a song to its details.
+type songResponse struct {
+	repository.GetTrackByIDRow
+	Artists []string `json:"artists"`
+}
+
+// Lyrics returns the lyrics stored for a song and where they come from.
+func (c *SongsController) Lyrics(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	intId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid ID",
+		})
+	}
+
+	repo := repository.New(c.container.Database)
+	stored, err := repo.GetLyricsByTrackID(ctx.UserContext(), intId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "lyrics not found",
+			})
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(stored)
+}
+
+// Attempts returns the attempts made to fetch the lyrics of a song, most recent first.
+func (c *SongsController) Attempts(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	intId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid ID",
+		})
+	}
+
+	repo := repository.New(c.container.Database)
+	if _, err := repo.GetTrackByID(ctx.UserContext(), intId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "song not found",
+			})
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	attempts, err := repo.GetLyricsAttemptsByTrackID(ctx.UserContext(), intId)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if attempts == nil {
+		attempts = []repository.GetLyricsAttemptsByTrackIDRow{}
+	}
+
+	return ctx.JSON(attempts)
+}
+
+// statusRequest is the body of a status request.
+type statusRequest struct {
+	// Status is either "ignored" or "pending" to search the lyrics again
+	Status string `json:"status"`
+}
+
+// Status ignores a song, or searches its lyrics again.
+func (c *SongsController) Status(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	intId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid ID",
+		})
+	}
+
+	var req statusRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid body",
+		})
+	}
+
+	song, err := lyrics.SetStatus(ctx.UserContext(), c.container, intId, music.LyricsStatus(req.Status))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "song not found",
+			})
+		case errors.Is(err, music.ErrInvalidLyricsStatus):
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, music.ErrInvalidLyricsStatusTransition):
+			return ctx.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if song.LyricsStatus == string(music.LyricsStatusPending) {
+		if _, err := c.container.Tasks.Add(tasks.DownloadLyricsTask{Path: song.Path}).Save(); err != nil {
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return ctx.JSON(song)
+}
+
+// Cover returns the cover art thumbnail of a song.
+func (c *SongsController) Cover(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	intId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid ID",
+		})
+	}
+
+	repo := repository.New(c.container.Database)
+	song, err := repo.GetTrackByID(ctx.UserContext(), intId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "song not found",
+			})
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Thumbnails cached when the song was persisted are served without reading its tags
+	if thumbnail, ok := c.container.Covers.Cached(song.AudioPath); ok {
+		return ctx.SendFile(thumbnail)
+	}
+
+	track, err := music.ExtractMetadata(song.Path, c.container.MetadataOptions...)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	thumbnail, err := c.container.Covers.Thumbnail(track)
+	if err != nil {
+		if errors.Is(err, music.ErrNoCover) {
+			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "cover not found",
+			})
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.SendFile(thumbnail)
+}
+
+// offsetRequest is the body of an offset request.
+type offsetRequest struct {
+	// Offset delays the lyrics by the given milliseconds, advancing them when negative
+	Offset int64 `json:"offset"`
+	// Mode is either "rewrite" (default) or "tag"
+	Mode string `json:"mode"`
+	// Album applies the offset to every track of the album
+	Album bool `json:"album"`
+}
+
+// Offset shifts the synced lyrics of a song, or of its whole album.
+func (c *SongsController) Offset(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	intId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid ID",
+		})
+	}
+
+	var req offsetRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid body",
+		})
+	}
+
+	mode := lyrics.OffsetModeRewrite
+	if req.Mode != "" {
+		mode = lyrics.OffsetMode(req.Mode)
+	}
+
+	tracks, err := lyrics.Shift(ctx.UserContext(), c.container, lyrics.ShiftOptions{
+		TrackID:    intId,
+		Offset:     time.Duration(req.Offset) * time.Millisecond,
+		Mode:       mode,
+		WholeAlbum: req.Album,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "song not found",
+			})
+		case errors.Is(err, lyrics.ErrNoAlbumTracks):
+			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, lyrics.ErrInvalidOffsetMode), errors.Is(err, lyrics.ErrNoSyncedLyrics):
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.JSON(tracks)
+}
+
+// instrumentalRequest is the body of an instrumental request.
+type instrumentalRequest struct {
+	Instrumental bool `json:"instrumental"`
+}
+
+// Instrumental marks a song as instrumental, or not, overriding the provider and the tags.
+func (c *SongsController) Instrumental(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	intId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid ID",
+		})
+	}
+
+	var req instrumentalRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid body",
+		})
+	}
+
+	song, err := lyrics.SetInstrumental(ctx.UserContext(), c.container, intId, req.Instrumental)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "song not found",
+			})
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// A song no longer instrumental gets its lyrics searched again
+	if song.LyricsStatus == string(music.LyricsStatusPending) {
+		if _, err := c.container.Tasks.Add(tasks.DownloadLyricsTask{Path: song.Path}).Save(); err != nil {
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
 	return ctx.JSON(song)
 }
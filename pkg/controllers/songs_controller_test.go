@@ -0,0 +1,108 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gerald-lbn/refrain/config"
+	"github.com/gerald-lbn/refrain/pkg/controllers"
+	"github.com/gerald-lbn/refrain/pkg/music"
+	"github.com/gerald-lbn/refrain/pkg/repository"
+	"github.com/gerald-lbn/refrain/pkg/services"
+	dbUtils "github.com/gerald-lbn/refrain/pkg/utils/db"
+	"github.com/gerald-lbn/refrain/pkg/utils/db/dbtest"
+	"github.com/gofiber/fiber/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SongsController", func() {
+	var (
+		ctx     context.Context
+		repo    *repository.Queries
+		app     *fiber.App
+		trackID int64
+	)
+
+	get := func(path string) (*http.Response, map[string]any, []map[string]any) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		if resp.StatusCode != fiber.StatusOK {
+			var body map[string]any
+			Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+			return resp, body, nil
+		}
+
+		var list []map[string]any
+		Expect(json.NewDecoder(resp.Body).Decode(&list)).To(Succeed())
+		return resp, nil, list
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c := &services.Container{Config: &config.Config{}, Database: dbtest.New(ctx)}
+		repo = repository.New(c.Database)
+
+		app = fiber.New()
+		app.Get("/api/tracks/:id/attempts", controllers.NewSongsController(c).Attempts)
+
+		Expect(repo.CreateTrack(ctx, repository.CreateTrackParams{
+			Path:   "/music/Sleep Token/Vore.flac",
+			Title:  dbUtils.StringToNullString("Vore"),
+			Artist: dbUtils.StringToNullString("Sleep Token"),
+		})).To(Succeed())
+		track, err := repo.GetTrackByPath(ctx, "/music/Sleep Token/Vore.flac")
+		Expect(err).ToNot(HaveOccurred())
+		trackID = track.ID
+	})
+
+	When("listing the lyrics attempts of a song", func() {
+		It("should return them most recent first", func() {
+			now := time.Now()
+			for i, outcome := range []music.LyricsAttemptOutcome{music.LyricsAttemptMiss, music.LyricsAttemptHit} {
+				Expect(repo.CreateLyricsAttempt(ctx, repository.CreateLyricsAttemptParams{
+					TrackID:     trackID,
+					AttemptedAt: now.Add(time.Duration(i) * time.Minute),
+					Provider:    string(music.LyricsProviderLRCLib),
+					TrackName:   dbUtils.StringToNullString("Vore"),
+					ArtistName:  dbUtils.StringToNullString("Sleep Token"),
+					Outcome:     string(outcome),
+					ProviderID:  dbUtils.IntToNullInt64(42 * i),
+				})).To(Succeed())
+			}
+
+			resp, _, attempts := get(fmt.Sprintf("/api/tracks/%d/attempts", trackID))
+			Expect(resp.StatusCode).To(Equal(fiber.StatusOK))
+			Expect(attempts).To(HaveLen(2))
+			Expect(attempts[0]["outcome"]).To(Equal(string(music.LyricsAttemptHit)))
+			Expect(attempts[0]["provider_id"]).To(BeEquivalentTo(42))
+			Expect(attempts[0]["track_name"]).To(Equal("Vore"))
+			Expect(attempts[1]["outcome"]).To(Equal(string(music.LyricsAttemptMiss)))
+		})
+
+		It("should return an empty list for a song never searched", func() {
+			resp, _, attempts := get(fmt.Sprintf("/api/tracks/%d/attempts", trackID))
+			Expect(resp.StatusCode).To(Equal(fiber.StatusOK))
+			Expect(attempts).To(BeEmpty())
+			Expect(attempts).ToNot(BeNil())
+		})
+
+		It("should return 404 for an unknown song", func() {
+			resp, body, _ := get(fmt.Sprintf("/api/tracks/%d/attempts", trackID+1))
+			Expect(resp.StatusCode).To(Equal(fiber.StatusNotFound))
+			Expect(body["error"]).To(Equal("song not found"))
+		})
+
+		It("should return 400 for an invalid ID", func() {
+			resp, body, _ := get("/api/tracks/vore/attempts")
+			Expect(resp.StatusCode).To(Equal(fiber.StatusBadRequest))
+			Expect(body["error"]).To(Equal("invalid ID"))
+		})
+	})
+})
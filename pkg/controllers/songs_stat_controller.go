@@ -1,6 +1,9 @@
 package controllers
 
 import (
+	"errors"
+
+	"github.com/gerald-lbn/refrain/pkg/library"
 	"github.com/gerald-lbn/refrain/pkg/repository"
 	"github.com/gerald-lbn/refrain/pkg/services"
 	"github.com/gofiber/fiber/v2"
@@ -16,6 +19,12 @@ func NewSongsStatController(container *services.Container) *SongsStatController
 	}
 }
 
+// statsResponse adds the breakdown by audio format to the library statistics.
+type statsResponse struct {
+	repository.GetStatsRow
+	Formats []repository.GetFormatStatsRow `json:"formats"`
+}
+
 // Index returns statistics about songs
 func (c *SongsStatController) Index(ctx *fiber.Ctx) error {
 	repo := repository.New(c.container.Database)
@@ -26,5 +35,34 @@ func (c *SongsStatController) Index(ctx *fiber.Ctx) error {
 		})
 	}
 
-	return ctx.JSON(stats)
+	formats, err := repo.GetFormatStats(ctx.UserContext())
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(statsResponse{GetStatsRow: stats, Formats: formats})
+}
+
+// History returns the daily snapshots of the lyrics coverage of a library, the whole collection by
+// default, between the from and to days, the last 30 days by default. Libraries that are not
+// configured are rejected.
+func (c *SongsStatController) History(ctx *fiber.Ctx) error {
+	snapshots, err := library.History(ctx.UserContext(), c.container.Database, c.container.Config.Libraries.Paths, library.HistoryOptions{
+		Library: ctx.Query("library"),
+		From:    ctx.Query("from"),
+		To:      ctx.Query("to"),
+	})
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, library.ErrInvalidDay) || errors.Is(err, library.ErrUnknownLibrary) {
+			status = fiber.StatusBadRequest
+		}
+		return ctx.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.JSON(snapshots)
 }
@@ -6,9 +6,12 @@ import (
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/gerald-lbn/refrain/pkg/log"
+	"github.com/gerald-lbn/refrain/pkg/lyrics"
+	"github.com/gerald-lbn/refrain/pkg/music"
 	"github.com/gerald-lbn/refrain/pkg/repository"
 	"github.com/gerald-lbn/refrain/pkg/services"
 	"github.com/gerald-lbn/refrain/pkg/tasks"
+	dbUtils "github.com/gerald-lbn/refrain/pkg/utils/db"
 	"github.com/gerald-lbn/refrain/pkg/utils/file"
 )
 
@@ -25,22 +28,102 @@ func HandleCreate(c *services.Container, ctx context.Context) services.FileEvent
 			return nil
 		}
 
+		// Lyrics files added by the user are converted to UTF-8 so every player can read them
+		if music.IsLyricsFile(event.Name) {
+			if !c.LyricsPaths.Matches(event.Name) {
+				return nil
+			}
+			return normalizeLyricsFile(c, event.Name)
+		}
+
+		// A CUE sheet splits its audio file into tracks, replacing the whole file
+		if music.IsCueSheet(event.Name) {
+			paths, err := music.CueSheetTracks(event.Name)
+			if err != nil {
+				return err
+			}
+
+			repo := repository.New(c.Database)
+			for _, p := range paths {
+				if err := repo.DeleteTrack(ctx, music.AudioFilePath(p)); err != nil {
+					return err
+				}
+
+				if err := enqueueTrack(c, p); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
 		if isAudio, err := file.IsAudioFile(event.Name); err != nil {
 			return err
 		} else if !isAudio {
 			return nil
 		}
 
-		_, err := c.Tasks.Add(tasks.DownloadLyricsTask{
-			Path: event.Name,
-		}).Wait(5 * time.Second).Save()
-
+		sheetPath, _, err := music.FindCueSheet(event.Name)
 		if err != nil {
 			return err
 		}
 
+		if sheetPath != "" {
+			paths, err := music.CueSheetTracks(sheetPath)
+			if err != nil {
+				return err
+			}
+
+			for _, p := range paths {
+				if music.AudioFilePath(p) != event.Name {
+					continue
+				}
+
+				if err := enqueueTrack(c, p); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		return enqueueTrack(c, event.Name)
+	}
+}
+
+// enqueueTrack enqueues the tasks persisting the track at p and downloading its lyrics.
+func enqueueTrack(c *services.Container, p string) error {
+	_, err := c.Tasks.Add(tasks.PersistTrackInfoTask{
+		Path: p,
+	}).Save()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Tasks.Add(tasks.DownloadLyricsTask{
+		Path: p,
+	}).Wait(5 * time.Second).Save()
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// normalizeLyricsFile converts the lyrics file at p to UTF-8, when enabled.
+func normalizeLyricsFile(c *services.Container, p string) error {
+	if !c.Config.Lyrics.Encoding.Normalize {
 		return nil
 	}
+
+	ending, err := music.ParseLineEnding(c.Config.Lyrics.Encoding.LineEnding)
+	if err != nil {
+		return err
+	}
+
+	return lyrics.NormalizeEncoding(p, ending)
 }
 
 // HandleDelete handles delete events emitted by the file system watcher.
@@ -52,7 +135,15 @@ func HandleDelete(c *services.Container, ctx context.Context) services.FileEvent
 
 		// Since the file is deleted, there is no way to know what has been deleted except for the path
 		repo := repository.New(c.Database)
-		err := repo.DeleteTrack(ctx, event.Name)
+		if err := repo.DeleteTrack(ctx, event.Name); err != nil {
+			return err
+		}
+
+		// The tracks of a CUE sheet go away with their sheet or their audio file
+		err := repo.DeleteCueSheetTracks(ctx, repository.DeleteCueSheetTracksParams{
+			AudioPath:    dbUtils.StringToNullString(event.Name),
+			CueSheetPath: dbUtils.StringToNullString(event.Name),
+		})
 
 		// TODO: Remove potential tasks in queue which have the deleted track as a dependency
 
@@ -0,0 +1,124 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/repository"
+)
+
+const (
+	// DAY_LAYOUT is the format of the days snapshots are recorded for.
+	DAY_LAYOUT = "2006-01-02"
+	// DEFAULT_HISTORY_DAYS is the number of days of history returned when no start is given.
+	DEFAULT_HISTORY_DAYS = 30
+)
+
+var (
+	ErrInvalidDay     = errors.New("invalid day, expected YYYY-MM-DD")
+	ErrUnknownLibrary = errors.New("library is not configured")
+)
+
+// RecordSnapshots records the lyrics coverage of the whole collection, under an empty library, and of
+// each library on the day of t. Recording again on the same day replaces the snapshots of that day,
+// so they reflect the library at the end of the day.
+func RecordSnapshots(ctx context.Context, db *sql.DB, libraries []string, t time.Time) error {
+	repo := repository.New(db)
+	day := t.UTC().Format(DAY_LAYOUT)
+
+	scopes := append([]string{""}, libraries...)
+	for _, library := range scopes {
+		pattern := "%"
+		if library != "" {
+			// The separator keeps /music from covering /music-videos
+			pattern = escapeLike(strings.TrimSuffix(library, "/")+"/") + "%"
+		}
+
+		stats, err := repo.GetCoverageStats(ctx, pattern)
+		if err != nil {
+			return err
+		}
+
+		if err := repo.UpsertStatsSnapshot(ctx, repository.UpsertStatsSnapshotParams{
+			Day:                    day,
+			Library:                library,
+			TotalTracks:            stats.TotalTracks,
+			TracksWithSyncedLyrics: stats.TracksWithSyncedLyrics,
+			TracksWithPlainLyrics:  stats.TracksWithPlainLyrics,
+			InstrumentalTracks:     stats.InstrumentalTracks,
+			TracksMissingMetadata:  stats.TracksMissingMetadata,
+			RecordedAt:             t,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HistoryOptions tells which snapshots to return.
+type HistoryOptions struct {
+	// Library is the path of a library, with or without trailing slash, the whole collection when empty
+	Library string
+	// From is the first day returned as YYYY-MM-DD, DEFAULT_HISTORY_DAYS before To by default
+	From string
+	// To is the last day returned as YYYY-MM-DD, today by default
+	To string
+}
+
+// History returns the daily snapshots of one of the given libraries, oldest first.
+// It returns ErrUnknownLibrary when the library is not one of them.
+func History(ctx context.Context, db *sql.DB, libraries []string, opts HistoryOptions) ([]repository.GetStatsSnapshotsRow, error) {
+	library, ok := configuredLibrary(libraries, opts.Library)
+	if !ok {
+		return nil, ErrUnknownLibrary
+	}
+
+	to := time.Now().UTC()
+	if opts.To != "" {
+		var err error
+		if to, err = time.Parse(DAY_LAYOUT, opts.To); err != nil {
+			return nil, ErrInvalidDay
+		}
+	}
+
+	from := to.AddDate(0, 0, -DEFAULT_HISTORY_DAYS)
+	if opts.From != "" {
+		var err error
+		if from, err = time.Parse(DAY_LAYOUT, opts.From); err != nil {
+			return nil, ErrInvalidDay
+		}
+	}
+
+	snapshots, err := repository.New(db).GetStatsSnapshots(ctx, repository.GetStatsSnapshotsParams{
+		Library: library,
+		FromDay: from.Format(DAY_LAYOUT),
+		ToDay:   to.Format(DAY_LAYOUT),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if snapshots == nil {
+		snapshots = []repository.GetStatsSnapshotsRow{}
+	}
+
+	return snapshots, nil
+}
+
+// configuredLibrary returns the library as configured, which its snapshots are recorded under, and
+// whether it is one of the given libraries. Trailing slashes are ignored on both sides.
+func configuredLibrary(libraries []string, library string) (string, bool) {
+	if library == "" {
+		return "", true
+	}
+
+	for _, configured := range libraries {
+		if strings.TrimSuffix(configured, "/") == strings.TrimSuffix(library, "/") {
+			return configured, true
+		}
+	}
+	return "", false
+}
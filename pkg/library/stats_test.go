@@ -0,0 +1,125 @@
+package library_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/library"
+	"github.com/gerald-lbn/refrain/pkg/repository"
+	dbUtils "github.com/gerald-lbn/refrain/pkg/utils/db"
+	"github.com/gerald-lbn/refrain/pkg/utils/db/dbtest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stats", func() {
+	var (
+		ctx      context.Context
+		database *sql.DB
+	)
+
+	libraries := []string{"/music", "/audiobooks/"}
+	day := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	createTrack := func(path, title string, synced, plain bool) {
+		Expect(repository.New(database).CreateTrack(ctx, repository.CreateTrackParams{
+			Path:            path,
+			Title:           dbUtils.StringToNullString(title),
+			Artist:          dbUtils.StringToNullString("Sleep Token"),
+			Album:           dbUtils.StringToNullString("Take Me Back To Eden"),
+			HasSyncedLyrics: synced,
+			HasPlainLyrics:  plain,
+		})).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		database = dbtest.New(ctx)
+
+		createTrack("/music/Vore.flac", "Vore", true, true)
+		createTrack("/music/Chokehold.flac", "", false, false)
+		createTrack("/music-videos/Vore.mkv", "Vore", false, true)
+		createTrack("/audiobooks/Chapter 1.mp3", "Chapter 1", false, true)
+	})
+
+	It("should record the coverage of the collection and of each library", func() {
+		Expect(library.RecordSnapshots(ctx, database, libraries, day)).To(Succeed())
+
+		all, err := library.History(ctx, database, libraries, library.HistoryOptions{To: "2026-10-19"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(all).To(HaveLen(1))
+		Expect(all[0].Day).To(Equal("2026-10-19"))
+		Expect(all[0].TotalTracks).To(BeEquivalentTo(4))
+		Expect(all[0].TracksWithPlainLyrics).To(BeEquivalentTo(3))
+
+		music, err := library.History(ctx, database, libraries, library.HistoryOptions{Library: "/music", To: "2026-10-19"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(music).To(HaveLen(1))
+		Expect(music[0].TotalTracks).To(BeEquivalentTo(2))
+		Expect(music[0].TracksWithSyncedLyrics).To(BeEquivalentTo(1))
+		Expect(music[0].TracksWithPlainLyrics).To(BeEquivalentTo(1))
+		Expect(music[0].TracksMissingMetadata).To(BeEquivalentTo(1))
+
+		audiobooks, err := library.History(ctx, database, libraries, library.HistoryOptions{Library: "/audiobooks/", To: "2026-10-19"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(audiobooks).To(HaveLen(1))
+		Expect(audiobooks[0].TotalTracks).To(BeEquivalentTo(1))
+	})
+
+	It("should keep one snapshot per day", func() {
+		Expect(library.RecordSnapshots(ctx, database, libraries, day.AddDate(0, 0, -1))).To(Succeed())
+		Expect(library.RecordSnapshots(ctx, database, libraries, day)).To(Succeed())
+		createTrack("/music/Aqua Regia.flac", "Aqua Regia", true, true)
+		Expect(library.RecordSnapshots(ctx, database, libraries, day.Add(time.Hour))).To(Succeed())
+
+		snapshots, err := library.History(ctx, database, libraries, library.HistoryOptions{Library: "/music", To: "2026-10-19"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snapshots).To(HaveLen(2))
+		Expect(snapshots[0].Day).To(Equal("2026-10-18"))
+		Expect(snapshots[0].TotalTracks).To(BeEquivalentTo(2))
+		Expect(snapshots[1].Day).To(Equal("2026-10-19"))
+		Expect(snapshots[1].TotalTracks).To(BeEquivalentTo(3))
+	})
+
+	It("should return the snapshots of the given days", func() {
+		for i := range 5 {
+			Expect(library.RecordSnapshots(ctx, database, nil, day.AddDate(0, 0, -i))).To(Succeed())
+		}
+
+		snapshots, err := library.History(ctx, database, libraries, library.HistoryOptions{From: "2026-10-16", To: "2026-10-18"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snapshots).To(HaveLen(3))
+		Expect(snapshots[0].Day).To(Equal("2026-10-16"))
+		Expect(snapshots[2].Day).To(Equal("2026-10-18"))
+
+		snapshots, err = library.History(ctx, database, libraries, library.HistoryOptions{Library: "/music"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snapshots).ToNot(BeNil())
+		Expect(snapshots).To(BeEmpty())
+	})
+
+	It("should ignore the trailing slashes of libraries", func() {
+		Expect(library.RecordSnapshots(ctx, database, libraries, day)).To(Succeed())
+
+		music, err := library.History(ctx, database, libraries, library.HistoryOptions{Library: "/music/", To: "2026-10-19"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(music).To(HaveLen(1))
+		Expect(music[0].TotalTracks).To(BeEquivalentTo(2))
+
+		audiobooks, err := library.History(ctx, database, libraries, library.HistoryOptions{Library: "/audiobooks", To: "2026-10-19"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(audiobooks).To(HaveLen(1))
+		Expect(audiobooks[0].TotalTracks).To(BeEquivalentTo(1))
+	})
+
+	It("should reject libraries that are not configured", func() {
+		_, err := library.History(ctx, database, libraries, library.HistoryOptions{Library: "/unknown"})
+		Expect(err).To(MatchError(library.ErrUnknownLibrary))
+	})
+
+	It("should reject invalid days", func() {
+		_, err := library.History(ctx, database, libraries, library.HistoryOptions{From: "19/10/2026"})
+		Expect(err).To(MatchError(library.ErrInvalidDay))
+	})
+})
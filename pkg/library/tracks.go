@@ -0,0 +1,387 @@
+// Package library lists the tracks of the library page by page.
+package library
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	"github.com/gerald-lbn/refrain/pkg/repository"
+)
+
+const (
+	// DEFAULT_PAGE_SIZE is the number of tracks returned when no limit is given.
+	DEFAULT_PAGE_SIZE = 50
+	// MAX_PAGE_SIZE is the highest number of tracks returned in a single page.
+	MAX_PAGE_SIZE = 500
+)
+
+var (
+	ErrInvalidSort   = errors.New("invalid sort column")
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrCursorWithOffset is returned when a page is asked both by cursor and by offset.
+	ErrCursorWithOffset = errors.New("cursor and offset cannot be used together")
+)
+
+// trackColumns selects a track the same way the GetAllTracks query does, so rows scan into
+// repository.GetAllTracksRow.
+const trackColumns = `t.id,
+    t.path,
+    CAST(COALESCE(t.title, '') AS TEXT),
+    CAST(COALESCE(t.artist, '') AS TEXT),
+    CAST(COALESCE(t.album_artist, t.artist, '') AS TEXT),
+    CAST(COALESCE(t.album, '') AS TEXT),
+    t.duration,
+    t.has_plain_lyrics,
+    t.has_synced_lyrics,
+    t.lyrics_offset,
+    CAST(COALESCE(t.lyrics_issue, '') AS TEXT),
+    CAST(COALESCE(t.track_number, 0) AS INTEGER),
+    CAST(COALESCE(t.disc_number, 0) AS INTEGER),
+    CAST(COALESCE(t.release_date, '') AS TEXT),
+    CAST(COALESCE(t.year, 0) AS INTEGER),
+    CAST(COALESCE(t.genre, '') AS TEXT),
+    CAST(COALESCE(t.composer, '') AS TEXT),
+    CAST(COALESCE(t.musicbrainz_recording_id, '') AS TEXT),
+    CAST(COALESCE(t.musicbrainz_release_id, '') AS TEXT),
+    CAST(COALESCE(t.isrc, '') AS TEXT),
+    CAST(COALESCE(t.format, '') AS TEXT),
+    CAST(COALESCE(t.bitrate, 0) AS INTEGER),
+    CAST(COALESCE(t.sample_rate, 0) AS INTEGER),
+    CAST(COALESCE(t.channels, 0) AS INTEGER),
+    CAST(COALESCE(t.file_size, 0) AS INTEGER),
+    CAST(COALESCE(t.audio_path, t.path) AS TEXT),
+    CAST(COALESCE(t.cue_sheet_path, '') AS TEXT),
+    t.start_offset,
+    t.instrumental,
+    CAST(COALESCE(t.instrumental_source, '') AS TEXT),
+    CAST(COALESCE(t.artist_id, 0) AS INTEGER),
+    CAST(COALESCE(t.album_id, 0) AS INTEGER),
+    t.lyrics_status`
+
+// sortColumns maps the columns tracks can be sorted by, named as in the JSON of a track, to the
+// expression sorting them. Missing values sort as empty so a cursor can always point past them.
+var sortColumns = map[string]string{
+	"id":                       "t.id",
+	"path":                     "t.path",
+	"title":                    "COALESCE(t.title, '') COLLATE NOCASE",
+	"artist":                   "COALESCE(t.artist, '') COLLATE NOCASE",
+	"album_artist":             "COALESCE(t.album_artist, t.artist, '') COLLATE NOCASE",
+	"album":                    "COALESCE(t.album, '') COLLATE NOCASE",
+	"duration":                 "t.duration",
+	"has_plain_lyrics":         "t.has_plain_lyrics",
+	"has_synced_lyrics":        "t.has_synced_lyrics",
+	"lyrics_offset":            "t.lyrics_offset",
+	"lyrics_issue":             "COALESCE(t.lyrics_issue, '')",
+	"track_number":             "COALESCE(t.track_number, 0)",
+	"disc_number":              "COALESCE(t.disc_number, 0)",
+	"release_date":             "COALESCE(t.release_date, '')",
+	"year":                     "COALESCE(t.year, 0)",
+	"genre":                    "COALESCE(t.genre, '') COLLATE NOCASE",
+	"composer":                 "COALESCE(t.composer, '') COLLATE NOCASE",
+	"musicbrainz_recording_id": "COALESCE(t.musicbrainz_recording_id, '')",
+	"musicbrainz_release_id":   "COALESCE(t.musicbrainz_release_id, '')",
+	"isrc":                     "COALESCE(t.isrc, '')",
+	"format":                   "COALESCE(t.format, '')",
+	"bitrate":                  "COALESCE(t.bitrate, 0)",
+	"sample_rate":              "COALESCE(t.sample_rate, 0)",
+	"channels":                 "COALESCE(t.channels, 0)",
+	"file_size":                "COALESCE(t.file_size, 0)",
+	"audio_path":               "COALESCE(t.audio_path, t.path)",
+	"cue_sheet_path":           "COALESCE(t.cue_sheet_path, '')",
+	"start_offset":             "t.start_offset",
+	"instrumental":             "t.instrumental",
+	"instrumental_source":      "COALESCE(t.instrumental_source, '')",
+	"artist_id":                "COALESCE(t.artist_id, 0)",
+	"album_id":                 "COALESCE(t.album_id, 0)",
+	"lyrics_status":            "t.lyrics_status",
+}
+
+// defaultSort is the order of the library when no sort column is given.
+var defaultSort = []string{"artist", "album", "disc_number", "track_number", "path"}
+
+// TrackFilters narrows down the listed tracks. Unset filters match every track.
+type TrackFilters struct {
+	HasPlainLyrics  *bool
+	HasSyncedLyrics *bool
+	Instrumental    *bool
+	// Artist matches any artist performing the track, ignoring case
+	Artist string
+	// Album matches the album title, ignoring case
+	Album string
+	// MissingMetadata matches the tracks without title, artist or album
+	MissingMetadata *bool
+	// PathPrefix matches the tracks stored under the given path
+	PathPrefix string
+	// Statuses matches the tracks in any of the given lyrics statuses
+	Statuses []music.LyricsStatus
+}
+
+// ListOptions tells which page of the library to list and in which order.
+type ListOptions struct {
+	TrackFilters
+	// Sort is the column to sort by, the library order by default
+	Sort       string
+	Descending bool
+	Limit      int
+	// Offset skips the given number of tracks
+	Offset int
+	// Cursor resumes the listing after the last track of a previous page, taking precedence over the
+	// offset as it stays correct while tracks are added or removed
+	Cursor string
+}
+
+// TrackPage is a page of the library.
+type TrackPage struct {
+	Tracks []repository.GetAllTracksRow `json:"tracks"`
+	// Total is the number of tracks matching the filters, across every page
+	Total  int64 `json:"total"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+	// NextCursor resumes the listing after this page, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ListTracks returns a page of the tracks matching the filters, along with how many tracks match.
+func ListTracks(ctx context.Context, db *sql.DB, opts ListOptions) (TrackPage, error) {
+	if opts.Cursor != "" && opts.Offset > 0 {
+		return TrackPage{}, ErrCursorWithOffset
+	}
+
+	keys := defaultSort
+	if opts.Sort != "" {
+		if _, ok := sortColumns[opts.Sort]; !ok {
+			return TrackPage{}, fmt.Errorf("%w: %s", ErrInvalidSort, opts.Sort)
+		}
+		keys = []string{opts.Sort}
+	}
+
+	// The ID breaks ties so every track has a single position in the order
+	expressions := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		expressions = append(expressions, sortColumns[key])
+	}
+	expressions = append(expressions, "t.id")
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DEFAULT_PAGE_SIZE
+	}
+	limit = min(limit, MAX_PAGE_SIZE)
+
+	where, args := filterClause(opts.TrackFilters)
+
+	var total int64
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tracks t"+where, args...).Scan(&total); err != nil {
+		return TrackPage{}, err
+	}
+
+	direction, comparison := "ASC", ">"
+	if opts.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if opts.Cursor != "" {
+		values, err := decodeCursor(opts.Cursor, len(expressions))
+		if err != nil {
+			return TrackPage{}, err
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+		where = and(where, fmt.Sprintf("(%s) %s (%s)", strings.Join(expressions, ", "), comparison, placeholders))
+		args = append(args, values...)
+	}
+
+	orderBy := make([]string, len(expressions))
+	for i, expression := range expressions {
+		orderBy[i] = expression + " " + direction
+	}
+
+	// One more track than asked tells whether there is a next page
+	query := fmt.Sprintf("SELECT %s, %s FROM tracks t%s ORDER BY %s LIMIT ? OFFSET ?",
+		trackColumns, strings.Join(expressions, ", "), where, strings.Join(orderBy, ", "))
+	args = append(args, limit+1, opts.Offset)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return TrackPage{}, err
+	}
+	defer rows.Close()
+
+	page := TrackPage{Tracks: []repository.GetAllTracksRow{}, Total: total, Limit: limit, Offset: opts.Offset}
+	var last []any
+	for rows.Next() {
+		if len(page.Tracks) == limit {
+			cursor, err := encodeCursor(last)
+			if err != nil {
+				return TrackPage{}, err
+			}
+			page.NextCursor = cursor
+			break
+		}
+
+		var i repository.GetAllTracksRow
+		values := make([]any, len(expressions))
+		dest := []any{
+			&i.ID,
+			&i.Path,
+			&i.Title,
+			&i.Artist,
+			&i.AlbumArtist,
+			&i.Album,
+			&i.Duration,
+			&i.HasPlainLyrics,
+			&i.HasSyncedLyrics,
+			&i.LyricsOffset,
+			&i.LyricsIssue,
+			&i.TrackNumber,
+			&i.DiscNumber,
+			&i.ReleaseDate,
+			&i.Year,
+			&i.Genre,
+			&i.Composer,
+			&i.MusicbrainzRecordingID,
+			&i.MusicbrainzReleaseID,
+			&i.Isrc,
+			&i.Format,
+			&i.Bitrate,
+			&i.SampleRate,
+			&i.Channels,
+			&i.FileSize,
+			&i.AudioPath,
+			&i.CueSheetPath,
+			&i.StartOffset,
+			&i.Instrumental,
+			&i.InstrumentalSource,
+			&i.ArtistID,
+			&i.AlbumID,
+			&i.LyricsStatus,
+		}
+		for j := range values {
+			dest = append(dest, &values[j])
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return TrackPage{}, err
+		}
+		page.Tracks = append(page.Tracks, i)
+		last = values
+	}
+
+	return page, rows.Err()
+}
+
+// filterClause builds the WHERE clause of the filters and its arguments.
+func filterClause(filters TrackFilters) (string, []any) {
+	where, args := "", []any{}
+
+	if filters.HasPlainLyrics != nil {
+		where = and(where, "t.has_plain_lyrics = ?")
+		args = append(args, *filters.HasPlainLyrics)
+	}
+	if filters.HasSyncedLyrics != nil {
+		where = and(where, "t.has_synced_lyrics = ?")
+		args = append(args, *filters.HasSyncedLyrics)
+	}
+	if filters.Instrumental != nil {
+		where = and(where, "t.instrumental = ?")
+		args = append(args, *filters.Instrumental)
+	}
+	if artist := strings.TrimSpace(filters.Artist); artist != "" {
+		where = and(where, "t.id IN (SELECT track_id FROM track_artists WHERE artist = ? COLLATE NOCASE)")
+		args = append(args, artist)
+	}
+	if album := strings.TrimSpace(filters.Album); album != "" {
+		where = and(where, "t.album = ? COLLATE NOCASE")
+		args = append(args, album)
+	}
+	if filters.MissingMetadata != nil {
+		missing := "(COALESCE(t.title, '') = '' OR COALESCE(t.artist, '') = '' OR COALESCE(t.album, '') = '')"
+		if !*filters.MissingMetadata {
+			missing = "NOT " + missing
+		}
+		where = and(where, missing)
+	}
+	if filters.PathPrefix != "" {
+		where = and(where, `t.path LIKE ? ESCAPE '\'`)
+		args = append(args, escapeLike(filters.PathPrefix)+"%")
+	}
+
+	if len(filters.Statuses) > 0 {
+		where = and(where, "t.lyrics_status IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(filters.Statuses)), ", ")+")")
+		for _, status := range filters.Statuses {
+			args = append(args, string(status))
+		}
+	}
+
+	return where, args
+}
+
+// and adds a condition to a WHERE clause.
+func and(where, condition string) string {
+	if where == "" {
+		return " WHERE " + condition
+	}
+	return where + " AND " + condition
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern so s is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// encodeCursor encodes the sort values of a track into an opaque cursor.
+func encodeCursor(values []any) (string, error) {
+	for i, value := range values {
+		// Text columns may be scanned as bytes, which would be encoded as base64 in JSON
+		if b, ok := value.([]byte); ok {
+			values[i] = string(b)
+		}
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor decodes the sort values of a cursor, expecting one per sort expression.
+func decodeCursor(cursor string, count int) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+
+	var values []any
+	if err := decoder.Decode(&values); err != nil || len(values) != count {
+		return nil, ErrInvalidCursor
+	}
+
+	for i, value := range values {
+		switch v := value.(type) {
+		case json.Number:
+			// Integers are compared as such, so large ones don't lose precision as floats
+			if n, err := v.Int64(); err == nil {
+				values[i] = n
+			} else if f, err := v.Float64(); err == nil {
+				values[i] = f
+			} else {
+				return nil, ErrInvalidCursor
+			}
+		case string, bool, nil:
+		default:
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return values, nil
+}
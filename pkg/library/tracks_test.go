@@ -0,0 +1,151 @@
+package library_test
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/gerald-lbn/refrain/pkg/library"
+	"github.com/gerald-lbn/refrain/pkg/music"
+	"github.com/gerald-lbn/refrain/pkg/repository"
+	dbUtils "github.com/gerald-lbn/refrain/pkg/utils/db"
+	"github.com/gerald-lbn/refrain/pkg/utils/db/dbtest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tracks", func() {
+	var (
+		ctx      context.Context
+		database *sql.DB
+	)
+
+	createTrack := func(path, title, artist, album string, track int, synced bool) {
+		repo := repository.New(database)
+		Expect(repo.CreateTrack(ctx, repository.CreateTrackParams{
+			Path:            path,
+			Title:           dbUtils.StringToNullString(title),
+			Artist:          dbUtils.StringToNullString(artist),
+			Album:           dbUtils.StringToNullString(album),
+			TrackNumber:     dbUtils.IntToNullInt64(track),
+			HasSyncedLyrics: synced,
+		})).To(Succeed())
+
+		created, err := repo.GetTrackByPath(ctx, path)
+		Expect(err).ToNot(HaveOccurred())
+		if artist != "" {
+			Expect(repo.CreateTrackArtist(ctx, repository.CreateTrackArtistParams{TrackID: created.ID, Artist: artist})).To(Succeed())
+		}
+	}
+
+	paths := func(page library.TrackPage) []string {
+		p := []string{}
+		for _, track := range page.Tracks {
+			p = append(p, track.Path)
+		}
+		return p
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		database = dbtest.New(ctx)
+
+		createTrack("/music/Sleep Token/Take Me Back To Eden/02 Vore.flac", "Vore", "Sleep Token", "Take Me Back To Eden", 2, true)
+		createTrack("/music/Sleep Token/Take Me Back To Eden/01 Chokehold.flac", "Chokehold", "Sleep Token", "Take Me Back To Eden", 1, false)
+		createTrack("/music/Spiritbox/Eternal Blue/01 Sun Killer.flac", "Sun Killer", "Spiritbox", "Eternal Blue", 1, true)
+		createTrack("/music/100%_Unknown/track.mp3", "", "", "", 0, false)
+	})
+
+	It("should list the library in order with the total count", func() {
+		page, err := library.ListTracks(ctx, database, library.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(page.Total).To(BeEquivalentTo(4))
+		Expect(page.Limit).To(Equal(library.DEFAULT_PAGE_SIZE))
+		Expect(page.NextCursor).To(BeEmpty())
+		Expect(paths(page)).To(Equal([]string{
+			"/music/100%_Unknown/track.mp3",
+			"/music/Sleep Token/Take Me Back To Eden/01 Chokehold.flac",
+			"/music/Sleep Token/Take Me Back To Eden/02 Vore.flac",
+			"/music/Spiritbox/Eternal Blue/01 Sun Killer.flac",
+		}))
+	})
+
+	It("should page through the library by offset", func() {
+		page, err := library.ListTracks(ctx, database, library.ListOptions{Sort: "title", Limit: 2, Offset: 2})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(page.Total).To(BeEquivalentTo(4))
+		Expect(paths(page)).To(Equal([]string{
+			"/music/Spiritbox/Eternal Blue/01 Sun Killer.flac",
+			"/music/Sleep Token/Take Me Back To Eden/02 Vore.flac",
+		}))
+	})
+
+	It("should page through the library by cursor", func() {
+		seen := []string{}
+		opts := library.ListOptions{Sort: "title", Descending: true, Limit: 3}
+		for range 3 {
+			page, err := library.ListTracks(ctx, database, opts)
+			Expect(err).ToNot(HaveOccurred())
+			seen = append(seen, paths(page)...)
+			if page.NextCursor == "" {
+				break
+			}
+			opts.Cursor = page.NextCursor
+		}
+
+		Expect(seen).To(Equal([]string{
+			"/music/Sleep Token/Take Me Back To Eden/02 Vore.flac",
+			"/music/Spiritbox/Eternal Blue/01 Sun Killer.flac",
+			"/music/Sleep Token/Take Me Back To Eden/01 Chokehold.flac",
+			"/music/100%_Unknown/track.mp3",
+		}))
+	})
+
+	It("should filter the tracks", func() {
+		synced := false
+		page, err := library.ListTracks(ctx, database, library.ListOptions{TrackFilters: library.TrackFilters{
+			HasSyncedLyrics: &synced,
+			Artist:          "sleep token",
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(page.Total).To(BeEquivalentTo(1))
+		Expect(paths(page)).To(Equal([]string{"/music/Sleep Token/Take Me Back To Eden/01 Chokehold.flac"}))
+
+		missing := true
+		page, err = library.ListTracks(ctx, database, library.ListOptions{TrackFilters: library.TrackFilters{
+			MissingMetadata: &missing,
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(paths(page)).To(Equal([]string{"/music/100%_Unknown/track.mp3"}))
+
+		page, err = library.ListTracks(ctx, database, library.ListOptions{TrackFilters: library.TrackFilters{
+			Statuses: []music.LyricsStatus{music.LyricsStatusPending, music.LyricsStatusIgnored},
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(page.Total).To(BeEquivalentTo(4))
+	})
+
+	It("should match path prefixes literally", func() {
+		page, err := library.ListTracks(ctx, database, library.ListOptions{TrackFilters: library.TrackFilters{
+			PathPrefix: "/music/100%_",
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(page.Total).To(BeEquivalentTo(1))
+
+		page, err = library.ListTracks(ctx, database, library.ListOptions{TrackFilters: library.TrackFilters{
+			PathPrefix: "/music/1%",
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(page.Total).To(BeZero())
+	})
+
+	It("should reject invalid options", func() {
+		_, err := library.ListTracks(ctx, database, library.ListOptions{Sort: "id; DROP TABLE tracks"})
+		Expect(err).To(MatchError(library.ErrInvalidSort))
+
+		_, err = library.ListTracks(ctx, database, library.ListOptions{Cursor: "not a cursor"})
+		Expect(err).To(MatchError(library.ErrInvalidCursor))
+
+		_, err = library.ListTracks(ctx, database, library.ListOptions{Cursor: "W10", Offset: 10})
+		Expect(err).To(MatchError(library.ErrCursorWithOffset))
+	})
+})
@@ -0,0 +1,101 @@
+package lyrics
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/gerald-lbn/refrain/pkg/log"
+	"github.com/gerald-lbn/refrain/pkg/music"
+)
+
+// NormalizedFile reports a lyrics file rewritten as UTF-8, or left untouched because its encoding
+// is uncertain.
+type NormalizedFile struct {
+	Path string `json:"path"`
+	// Encoding is the encoding the file was detected in
+	Encoding music.Encoding `json:"encoding"`
+	// Skipped explains why the file was not rewritten
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// NormalizeEncodings rewrites every lyrics file found under the given paths as UTF-8 without byte
+// order mark, using the given line ending, and reports the files it changed along with the files
+// skipped because their encoding is uncertain. Only the files matching the lyrics path template are
+// lyrics, other text files are left alone. With dryRun, files are only reported and left untouched.
+func NormalizeEncodings(paths []string, template music.LyricsPathTemplate, ending music.LineEnding, dryRun bool) ([]NormalizedFile, error) {
+	if _, err := music.ParseLineEnding(string(ending)); err != nil {
+		return nil, err
+	}
+
+	changed := []NormalizedFile{}
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !template.Matches(p) {
+				return nil
+			}
+
+			enc, rewritten, err := normalizeEncoding(p, ending, dryRun)
+			if errors.Is(err, music.ErrUncertainEncoding) {
+				changed = append(changed, NormalizedFile{Path: p, Encoding: enc, Skipped: err.Error()})
+				return nil
+			}
+			if err != nil {
+				// A single unreadable file should not stop the whole library from being normalized
+				log.Default().Warn("failed to normalize lyrics file", "path", p, "error", err)
+				return nil
+			}
+
+			if rewritten {
+				changed = append(changed, NormalizedFile{Path: p, Encoding: enc})
+			}
+			return nil
+		})
+		if err != nil {
+			return changed, err
+		}
+	}
+
+	return changed, nil
+}
+
+// NormalizeEncoding rewrites the lyrics file at p as UTF-8 without byte order mark, using the given
+// line ending, and logs the change. Files whose encoding is uncertain are left untouched.
+func NormalizeEncoding(p string, ending music.LineEnding) error {
+	enc, rewritten, err := music.NormalizeLyricsFile(p, ending)
+	if errors.Is(err, music.ErrUncertainEncoding) {
+		log.Default().Warn("skipped lyrics file of uncertain encoding", "path", p)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if rewritten {
+		log.Default().Info("normalized lyrics file", "path", p, "encoding", enc, "line_ending", ending)
+	}
+	return nil
+}
+
+// normalizeEncoding normalizes the lyrics file at p, or only tells whether it would be rewritten.
+func normalizeEncoding(p string, ending music.LineEnding, dryRun bool) (music.Encoding, bool, error) {
+	if !dryRun {
+		return music.NormalizeLyricsFile(p, ending)
+	}
+
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return "", false, err
+	}
+
+	normalized, enc, err := music.NormalizeText(data, ending)
+	if err != nil {
+		return enc, false, err
+	}
+	return enc, !bytes.Equal(normalized, data), nil
+}
@@ -0,0 +1,47 @@
+package lyrics
+
+import (
+	"context"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	"github.com/gerald-lbn/refrain/pkg/repository"
+	"github.com/gerald-lbn/refrain/pkg/services"
+	dbUtils "github.com/gerald-lbn/refrain/pkg/utils/db"
+)
+
+// SetInstrumental records whether a track is instrumental as set by the user.
+// Instrumental tracks are no longer looked up for lyrics.
+func SetInstrumental(ctx context.Context, c *services.Container, id int64, instrumental bool) (repository.GetTrackByIDRow, error) {
+	repo := repository.New(c.Database)
+	if _, err := repo.GetTrackByID(ctx, id); err != nil {
+		return repository.GetTrackByIDRow{}, err
+	}
+
+	if err := repo.UpdateTrackInstrumental(ctx, repository.UpdateTrackInstrumentalParams{
+		Instrumental:       instrumental,
+		InstrumentalSource: dbUtils.StringToNullString(string(music.InstrumentalSourceManual)),
+		ID:                 id,
+	}); err != nil {
+		return repository.GetTrackByIDRow{}, err
+	}
+
+	track, err := repo.GetTrackByID(ctx, id)
+	if err != nil {
+		return repository.GetTrackByIDRow{}, err
+	}
+
+	// Ignored tracks stay ignored, the flag is still recorded for when they are no longer
+	if track.LyricsStatus == string(music.LyricsStatusIgnored) {
+		return track, nil
+	}
+
+	status := music.LyricsStatusOf(track.HasPlainLyrics, track.HasSyncedLyrics)
+	if instrumental {
+		status = music.LyricsStatusInstrumental
+	}
+	if err := UpdateStatus(ctx, repo, id, status); err != nil {
+		return repository.GetTrackByIDRow{}, err
+	}
+
+	return repo.GetTrackByID(ctx, id)
+}
@@ -0,0 +1,146 @@
+package lyrics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	"github.com/gerald-lbn/refrain/pkg/music/lrc"
+	"github.com/gerald-lbn/refrain/pkg/repository"
+	"github.com/gerald-lbn/refrain/pkg/services"
+	dbUtils "github.com/gerald-lbn/refrain/pkg/utils/db"
+)
+
+// OffsetMode tells how an offset is applied to synced lyrics.
+type OffsetMode string
+
+const (
+	// OffsetModeRewrite rewrites every timestamp of the synced lyrics.
+	OffsetModeRewrite OffsetMode = "rewrite"
+	// OffsetModeTag adjusts the [offset:] tag of the synced lyrics.
+	OffsetModeTag OffsetMode = "tag"
+)
+
+var (
+	ErrInvalidOffsetMode = errors.New("offset mode must be either rewrite or tag")
+	ErrNoSyncedLyrics    = errors.New("track has no synced lyrics")
+	// ErrNoAlbumTracks is returned when the album of a track can't be found to shift it whole.
+	ErrNoAlbumTracks = errors.New("no track found for the album")
+)
+
+// ShiftOptions describes an offset to apply to synced lyrics.
+type ShiftOptions struct {
+	// TrackID is the track whose synced lyrics are shifted
+	TrackID int64
+	// Offset delays the lyrics when positive and advances them when negative
+	Offset time.Duration
+	// Mode tells how the offset is applied
+	Mode OffsetMode
+	// WholeAlbum shifts every track of the album the track belongs to
+	WholeAlbum bool
+}
+
+// ShiftedTrack reports the outcome of a shift for a single track.
+type ShiftedTrack struct {
+	ID   int64  `json:"id"`
+	Path string `json:"path"`
+	// LyricsOffset is the total offset recorded for the track, in milliseconds
+	LyricsOffset int64 `json:"lyrics_offset"`
+	// Skipped explains why the track was not shifted
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// offsetTrack holds the fields of a track needed to shift its lyrics.
+type offsetTrack struct {
+	id           int64
+	path         string
+	lyricsOffset int64
+}
+
+// Shift applies an offset to the synced lyrics of a track, or of its whole album, and records the
+// total offset of each track so lyrics downloaded later get the same adjustment.
+func Shift(ctx context.Context, c *services.Container, opts ShiftOptions) ([]ShiftedTrack, error) {
+	if opts.Mode != OffsetModeRewrite && opts.Mode != OffsetModeTag {
+		return nil, ErrInvalidOffsetMode
+	}
+
+	repo := repository.New(c.Database)
+	track, err := repo.GetTrackByID(ctx, opts.TrackID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := []offsetTrack{{id: track.ID, path: track.Path, lyricsOffset: track.LyricsOffset}}
+	if opts.WholeAlbum && track.Album != "" {
+		albumTracks, err := repo.GetTracksByAlbum(ctx, repository.GetTracksByAlbumParams{
+			Album:       dbUtils.StringToNullString(track.Album),
+			AlbumArtist: track.AlbumArtist,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(albumTracks) == 0 {
+			return nil, ErrNoAlbumTracks
+		}
+
+		tracks = tracks[:0]
+		for _, t := range albumTracks {
+			tracks = append(tracks, offsetTrack{id: t.ID, path: t.Path, lyricsOffset: t.LyricsOffset})
+		}
+	}
+
+	shifted := make([]ShiftedTrack, 0, len(tracks))
+	for _, t := range tracks {
+		result, err := shiftTrack(ctx, c, repo, t, opts)
+		if err != nil {
+			if !opts.WholeAlbum {
+				return nil, err
+			}
+			result.Skipped = err.Error()
+		}
+		shifted = append(shifted, result)
+	}
+
+	return shifted, nil
+}
+
+// shiftTrack applies the offset to the synced lyrics file of a single track.
+func shiftTrack(ctx context.Context, c *services.Container, repo *repository.Queries, t offsetTrack, opts ShiftOptions) (ShiftedTrack, error) {
+	result := ShiftedTrack{ID: t.id, Path: t.path, LyricsOffset: t.lyricsOffset}
+
+	metadata, err := music.ExtractMetadata(t.path, c.MetadataOptions...)
+	if err != nil {
+		return result, err
+	}
+
+	if !metadata.HasSyncedLyrics {
+		return result, ErrNoSyncedLyrics
+	}
+
+	content, err := os.ReadFile(metadata.SyncedLyricsPath)
+	if err != nil {
+		return result, err
+	}
+
+	var updated string
+	switch opts.Mode {
+	case OffsetModeRewrite:
+		updated = lrc.Shift(string(content), opts.Offset)
+	case OffsetModeTag:
+		updated = lrc.Delay(string(content), opts.Offset)
+	}
+
+	if err := os.WriteFile(metadata.SyncedLyricsPath, []byte(updated), 0644); err != nil {
+		return result, err
+	}
+
+	result.LyricsOffset += opts.Offset.Milliseconds()
+	err = repo.UpdateTrackLyricsOffset(ctx, repository.UpdateTrackLyricsOffsetParams{
+		LyricsOffset: result.LyricsOffset,
+		ID:           t.id,
+	})
+
+	return result, err
+}
@@ -0,0 +1,210 @@
+package lyrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	"github.com/gerald-lbn/refrain/pkg/music/lrc"
+	dbUtils "github.com/gerald-lbn/refrain/pkg/utils/db"
+)
+
+const (
+	// SEARCH_INDEX_TABLE is the FTS5 table indexing the content of the lyrics table.
+	SEARCH_INDEX_TABLE = "lyrics_fts"
+	// DEFAULT_SEARCH_LIMIT is the number of results returned when no limit is given.
+	DEFAULT_SEARCH_LIMIT = 20
+	// MAX_SEARCH_LIMIT is the highest number of results returned by a single search.
+	MAX_SEARCH_LIMIT = 100
+)
+
+var ErrEmptyQuery = errors.New("search query must contain at least one word")
+
+const (
+	// snippetMarkStart and snippetMarkEnd surround the matching words of snippets until the text is
+	// escaped, control characters never appear in lyrics.
+	snippetMarkStart = "\x02"
+	snippetMarkEnd   = "\x03"
+)
+
+// snippetMarks replaces the markers of an escaped snippet by <mark> tags.
+var snippetMarks = strings.NewReplacer(snippetMarkStart, "<mark>", snippetMarkEnd, "</mark>")
+
+const searchIndexed = `SELECT
+    t.id,
+    t.path,
+    COALESCE(t.title, ''),
+    COALESCE(t.artist, ''),
+    COALESCE(t.album, ''),
+    snippet(lyrics_fts, -1, char(2), char(3), '…', 16),
+    COALESCE(l.plain_lyrics, ''),
+    COALESCE(l.synced_lyrics, '')
+FROM lyrics_fts
+JOIN lyrics l ON l.track_id = lyrics_fts.rowid
+JOIN tracks t ON t.id = l.track_id
+WHERE lyrics_fts MATCH ?
+ORDER BY rank
+LIMIT ?`
+
+const searchUnindexed = `SELECT
+    t.id,
+    t.path,
+    COALESCE(t.title, ''),
+    COALESCE(t.artist, ''),
+    COALESCE(t.album, ''),
+    '',
+    COALESCE(l.plain_lyrics, ''),
+    COALESCE(l.synced_lyrics, '')
+FROM lyrics l
+JOIN tracks t ON t.id = l.track_id
+WHERE l.plain_lyrics LIKE ? OR l.synced_lyrics LIKE ?
+ORDER BY t.artist, t.album, t.title
+LIMIT ?`
+
+// SearchResult is a track whose lyrics match a search.
+type SearchResult struct {
+	TrackID int64  `json:"track_id"`
+	Path    string `json:"path"`
+	Title   string `json:"title"`
+	Artist  string `json:"artist"`
+	Album   string `json:"album"`
+	// Snippet is an HTML excerpt of the lyrics around the match, with the matching words between <mark> tags
+	Snippet string `json:"snippet"`
+	// Line is the line of the lyrics matching the search
+	Line string `json:"line"`
+	// Timestamp is when the matching line starts in milliseconds, nil for plain lyrics
+	Timestamp *int64 `json:"timestamp"`
+}
+
+// Search returns the tracks whose lyrics contain the words of the query, best matches first.
+// Without full-text index, the lyrics containing the query as is are returned instead.
+func Search(ctx context.Context, db *sql.DB, query string, limit int) ([]SearchResult, error) {
+	words := searchWords(query)
+	if len(words) == 0 {
+		return nil, ErrEmptyQuery
+	}
+
+	if limit <= 0 {
+		limit = DEFAULT_SEARCH_LIMIT
+	}
+	limit = min(limit, MAX_SEARCH_LIMIT)
+
+	indexed, err := HasSearchIndex(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows *sql.Rows
+	if indexed {
+		rows, err = db.QueryContext(ctx, searchIndexed, matchExpression(words), limit)
+	} else {
+		pattern := dbUtils.Like(strings.TrimSpace(query))
+		rows, err = db.QueryContext(ctx, searchUnindexed, pattern, pattern, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		var result SearchResult
+		var plain, synced string
+		if err := rows.Scan(
+			&result.TrackID,
+			&result.Path,
+			&result.Title,
+			&result.Artist,
+			&result.Album,
+			&result.Snippet,
+			&plain,
+			&synced,
+		); err != nil {
+			return nil, err
+		}
+
+		result.Line, result.Timestamp = matchingLine(plain, synced, words)
+		result.Snippet = strings.TrimSpace(lrc.StripTimestamps(result.Snippet))
+		if result.Snippet == "" {
+			result.Snippet = result.Line
+		}
+		result.Snippet = snippetMarks.Replace(html.EscapeString(result.Snippet))
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// HasSearchIndex reports whether the full-text index of the lyrics can be queried. The index is
+// created by a migration when SQLite is built with FTS5, and can't be read without it.
+func HasSearchIndex(ctx context.Context, db *sql.DB) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx, `SELECT
+    (SELECT COUNT(*) FROM pragma_module_list WHERE name = 'fts5') *
+    (SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?)`, SEARCH_INDEX_TABLE).Scan(&count)
+	return count > 0, err
+}
+
+// matchExpression builds an FTS5 query matching lyrics containing every word, the last one being a
+// prefix so a line can be searched while it is typed. Words are quoted so user input is never
+// interpreted as FTS5 syntax.
+func matchExpression(words []string) string {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+	}
+	quoted[len(quoted)-1] += "*"
+	return strings.Join(quoted, " ")
+}
+
+// searchWords splits a query into lowercase words without diacritics.
+func searchWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(music.RemoveDiacritics(s)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+}
+
+// matchingLine returns the line of the lyrics containing the most words of the query, along with
+// its timestamp in milliseconds when the line comes from synced lyrics.
+func matchingLine(plain, synced string, words []string) (string, *int64) {
+	bestLine, bestScore := "", 0
+	var bestTimestamp *int64
+
+	for _, line := range lrc.Parse(synced) {
+		if score := matchingWords(line.Text, words); score > bestScore {
+			timestamp := line.Time.Milliseconds()
+			bestLine, bestScore, bestTimestamp = line.Text, score, &timestamp
+		}
+	}
+
+	// Plain lyrics only tell where the line is when the synced ones don't match
+	if bestScore < len(words) {
+		for line := range strings.Lines(plain) {
+			if score := matchingWords(line, words); score > bestScore {
+				bestLine, bestScore, bestTimestamp = strings.TrimSpace(line), score, nil
+			}
+		}
+	}
+
+	return bestLine, bestTimestamp
+}
+
+// matchingWords counts the words of the query found in line, the last one as a prefix.
+func matchingWords(line string, words []string) int {
+	lineWords := searchWords(line)
+
+	count := 0
+	for i, word := range words {
+		for _, lineWord := range lineWords {
+			if lineWord == word || (i == len(words)-1 && strings.HasPrefix(lineWord, word)) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,110 @@
+package lyrics_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/lyrics"
+	"github.com/gerald-lbn/refrain/pkg/music"
+	"github.com/gerald-lbn/refrain/pkg/repository"
+	dbUtils "github.com/gerald-lbn/refrain/pkg/utils/db"
+	"github.com/gerald-lbn/refrain/pkg/utils/db/dbtest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Search", func() {
+	var (
+		ctx      context.Context
+		database *sql.DB
+		indexed  bool
+	)
+
+	storeLyrics := func(path, title, plain, synced string) {
+		repo := repository.New(database)
+		Expect(repo.CreateTrack(ctx, repository.CreateTrackParams{
+			Path:   path,
+			Title:  dbUtils.StringToNullString(title),
+			Artist: dbUtils.StringToNullString("Sleep Token"),
+		})).To(Succeed())
+
+		track, err := repo.GetTrackByPath(ctx, path)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(repo.UpsertLyrics(ctx, repository.UpsertLyricsParams{
+			TrackID:      track.ID,
+			PlainLyrics:  dbUtils.StringToNullString(plain),
+			SyncedLyrics: dbUtils.StringToNullString(synced),
+			Provider:     string(music.LyricsProviderSidecar),
+			FetchedAt:    time.Now(),
+			ContentHash:  music.LyricsHash(plain, synced),
+		})).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+		database = dbtest.New(ctx)
+
+		indexed, err = lyrics.HasSearchIndex(ctx, database)
+		Expect(err).ToNot(HaveOccurred())
+
+		storeLyrics("/music/vore.flac", "Vore",
+			"You have become the voice in my head\nWelcome me in",
+			"[00:15.27] You have become the voice in my head\n[00:20.00] Welcome me in",
+		)
+		storeLyrics("/music/granite.flac", "Granite", "Fall into the fire of the granite", "")
+	})
+
+	It("should return the matching line and its timestamp for synced lyrics", func() {
+		results, err := lyrics.Search(ctx, database, "voice in my", 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Title).To(Equal("Vore"))
+		Expect(results[0].Line).To(Equal("You have become the voice in my head"))
+		Expect(results[0].Timestamp).To(HaveValue(Equal(int64(15270))))
+		Expect(results[0].Snippet).ToNot(ContainSubstring("[00:"))
+		if indexed {
+			Expect(results[0].Snippet).To(ContainSubstring("<mark>voice</mark>"))
+		}
+	})
+
+	It("should return the matching line of plain lyrics without timestamp", func() {
+		results, err := lyrics.Search(ctx, database, "the granite", 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Line).To(Equal("Fall into the fire of the granite"))
+		Expect(results[0].Timestamp).To(BeNil())
+	})
+
+	It("should escape the lyrics of snippets", func() {
+		storeLyrics("/music/aqua.flac", "Aqua Regia", "Rock & <b>roll</b> forever", "")
+
+		results, err := lyrics.Search(ctx, database, "forever", 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Line).To(Equal("Rock & <b>roll</b> forever"))
+		Expect(results[0].Snippet).To(ContainSubstring("Rock &amp; &lt;b&gt;roll&lt;/b&gt;"))
+		if indexed {
+			Expect(results[0].Snippet).To(ContainSubstring("<mark>forever</mark>"))
+		}
+	})
+
+	It("should reject queries without words", func() {
+		_, err := lyrics.Search(ctx, database, " ?! ", 0)
+		Expect(err).To(MatchError(lyrics.ErrEmptyQuery))
+	})
+
+	It("should keep the index up to date", func() {
+		if !indexed {
+			Skip("SQLite was built without FTS5")
+		}
+
+		Expect(repository.New(database).DeleteTrack(ctx, "/music/granite.flac")).To(Succeed())
+
+		results, err := lyrics.Search(ctx, database, "granite", 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(BeEmpty())
+	})
+})
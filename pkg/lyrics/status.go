@@ -0,0 +1,47 @@
+package lyrics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	"github.com/gerald-lbn/refrain/pkg/repository"
+	"github.com/gerald-lbn/refrain/pkg/services"
+)
+
+// UpdateStatus moves a track to the given lyrics status, returning
+// music.ErrInvalidLyricsStatusTransition when its current status does not allow it.
+func UpdateStatus(ctx context.Context, repo *repository.Queries, id int64, next music.LyricsStatus) error {
+	track, err := repo.GetTrackByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	current := music.LyricsStatus(track.LyricsStatus)
+	if current == next {
+		return nil
+	}
+	if !current.CanTransitionTo(next) {
+		return fmt.Errorf("%w: from %s to %s", music.ErrInvalidLyricsStatusTransition, current, next)
+	}
+
+	return repo.UpdateTrackLyricsStatus(ctx, repository.UpdateTrackLyricsStatusParams{
+		LyricsStatus: string(next),
+		ID:           id,
+	})
+}
+
+// SetStatus records the lyrics status of a track as set by the user, who can only ignore a track or
+// ask for its lyrics again.
+func SetStatus(ctx context.Context, c *services.Container, id int64, status music.LyricsStatus) (repository.GetTrackByIDRow, error) {
+	if status != music.LyricsStatusIgnored && status != music.LyricsStatusPending {
+		return repository.GetTrackByIDRow{}, fmt.Errorf("%w: %s can't be set manually", music.ErrInvalidLyricsStatus, status)
+	}
+
+	repo := repository.New(c.Database)
+	if err := UpdateStatus(ctx, repo, id, status); err != nil {
+		return repository.GetTrackByIDRow{}, err
+	}
+
+	return repo.GetTrackByID(ctx, id)
+}
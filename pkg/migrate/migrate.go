@@ -0,0 +1,334 @@
+// Package migrate applies dbmate-style SQL migrations and records the applied versions in the
+// schema_migrations table.
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	UP_MARKER   = "-- migrate:up"
+	DOWN_MARKER = "-- migrate:down"
+	// REQUIRES_OPTION follows the up marker to list the SQLite modules a migration needs, such as
+	// "-- migrate:up requires:fts5".
+	REQUIRES_OPTION = "requires:"
+)
+
+var (
+	ErrInvalidMigration  = errors.New("invalid migration")
+	ErrNothingToRollBack = errors.New("no migration to roll back")
+	// ErrSchemaTooNew is returned when the database was migrated by a newer version of the application.
+	ErrSchemaTooNew = errors.New("database schema is newer than the application")
+	// ErrMissingModule is returned when an applied migration needs a module SQLite was built without.
+	ErrMissingModule = errors.New("database needs a module SQLite was built without")
+)
+
+// Migration is a versioned change to the database schema.
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+	// Requires lists the SQLite modules the migration needs. It stays pending until they are available.
+	Requires []string
+}
+
+// Status tells whether a migration is applied.
+type Status struct {
+	Version uint64 `json:"version"`
+	Name    string `json:"name"`
+	Applied bool   `json:"applied"`
+}
+
+// Load reads the migrations stored in dir, named after their version such as
+// 20251116073135_create_tracks_table.sql, sorted by version.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := []Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, err := parse(entry.Name(), string(content))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("%w: version %d is used twice", ErrInvalidMigration, migrations[i].Version)
+		}
+	}
+
+	return migrations, nil
+}
+
+// parse reads the version and name of a migration from its file name, and its statements from
+// the migrate:up and migrate:down sections of its content.
+func parse(filename, content string) (Migration, error) {
+	version, name, ok := strings.Cut(strings.TrimSuffix(filename, ".sql"), "_")
+	if !ok {
+		return Migration{}, fmt.Errorf("%w: %s is not named VERSION_NAME.sql", ErrInvalidMigration, filename)
+	}
+
+	v, err := strconv.ParseUint(version, 10, 64)
+	if err != nil {
+		return Migration{}, fmt.Errorf("%w: %s has no numeric version", ErrInvalidMigration, filename)
+	}
+
+	migration := Migration{Version: v, Name: name}
+	var up, down strings.Builder
+	var section *strings.Builder
+	for line := range strings.Lines(content) {
+		switch trimmed := strings.TrimSpace(line); {
+		case strings.HasPrefix(trimmed, UP_MARKER):
+			section = &up
+			for _, option := range strings.Fields(strings.TrimPrefix(trimmed, UP_MARKER)) {
+				if modules, ok := strings.CutPrefix(option, REQUIRES_OPTION); ok {
+					migration.Requires = append(migration.Requires, strings.Split(modules, ",")...)
+				}
+			}
+		case strings.HasPrefix(trimmed, DOWN_MARKER):
+			section = &down
+		case section != nil:
+			section.WriteString(line)
+		}
+	}
+
+	migration.Up = strings.TrimSpace(up.String())
+	migration.Down = strings.TrimSpace(down.String())
+	if migration.Up == "" {
+		return Migration{}, fmt.Errorf("%w: %s has no %s section", ErrInvalidMigration, filename, UP_MARKER)
+	}
+
+	return migration, nil
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New creates a Migrator applying the given migrations, sorted by version, to db.
+func New(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Up applies every pending migration in order and returns the applied ones.
+// Each migration runs in its own transaction, so a failing migration leaves the previous ones applied.
+// Migrations requiring a module SQLite was built without are left pending.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if err := m.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := []Migration{}
+	for _, migration := range m.migrations {
+		if applied[migration.Version] {
+			continue
+		}
+
+		missing, err := m.missingModules(ctx, migration)
+		if err != nil {
+			return done, err
+		}
+		if len(missing) > 0 {
+			continue
+		}
+
+		err = m.run(ctx, migration.Up, "INSERT INTO schema_migrations (version, dirty) VALUES (?, false)", migration.Version)
+		if err != nil {
+			return done, fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down rolls back the most recent applied migration and returns it.
+func (m *Migrator) Down(ctx context.Context) (Migration, error) {
+	if err := m.Check(ctx); err != nil {
+		return Migration{}, err
+	}
+
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+
+	for _, migration := range slices.Backward(m.migrations) {
+		if !applied[migration.Version] {
+			continue
+		}
+
+		err := m.run(ctx, migration.Down, "DELETE FROM schema_migrations WHERE version = ?", migration.Version)
+		if err != nil {
+			return migration, fmt.Errorf("failed to roll back migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		return migration, nil
+	}
+
+	return Migration{}, ErrNothingToRollBack
+}
+
+// Status lists every known migration and whether it is applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		statuses = append(statuses, Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: applied[migration.Version],
+		})
+	}
+
+	return statuses, nil
+}
+
+// Latest returns the version of the most recent known migration, zero without migrations.
+func (m *Migrator) Latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// CheckVersion returns ErrSchemaTooNew when version is more recent than every known migration.
+func (m *Migrator) CheckVersion(version uint64) error {
+	if latest := m.Latest(); version > latest {
+		return fmt.Errorf("%w: version %d is applied but the latest known is %d", ErrSchemaTooNew, version, latest)
+	}
+	return nil
+}
+
+// Check returns ErrSchemaTooNew when the database has a migration applied that is unknown and more
+// recent than every known migration, meaning it was migrated by a newer version of the application.
+// It returns ErrMissingModule when an applied migration requires a module SQLite was built without,
+// since the tables and triggers it created would make statements fail.
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for version := range applied {
+		if err := m.CheckVersion(version); err != nil {
+			return err
+		}
+	}
+
+	for _, migration := range m.migrations {
+		if !applied[migration.Version] {
+			continue
+		}
+
+		missing, err := m.missingModules(ctx, migration)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%w: migration %d_%s requires %s", ErrMissingModule, migration.Version, migration.Name, strings.Join(missing, ", "))
+		}
+	}
+
+	return nil
+}
+
+// missingModules returns the modules required by the migration that SQLite does not provide.
+func (m *Migrator) missingModules(ctx context.Context, migration Migration) ([]string, error) {
+	var missing []string
+	for _, module := range migration.Requires {
+		var count int
+		if err := m.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_module_list WHERE name = ?", module).Scan(&count); err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			missing = append(missing, module)
+		}
+	}
+	return missing, nil
+}
+
+// appliedVersions returns the versions of the applied migrations, creating the schema_migrations
+// table when it does not exist yet.
+func (m *Migrator) appliedVersions(ctx context.Context) (map[uint64]bool, error) {
+	if _, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version uint64,dirty bool);
+CREATE UNIQUE INDEX IF NOT EXISTS version_unique ON schema_migrations (version);`); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[uint64]bool{}
+	for rows.Next() {
+		var version uint64
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
+
+// run executes the statements of a migration and records it in a single transaction.
+func (m *Migrator) run(ctx context.Context, statements, record string, version uint64) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if statements != "" {
+		if _, err := tx.ExecContext(ctx, statements); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, record, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
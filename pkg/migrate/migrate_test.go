@@ -0,0 +1,161 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing/fstest"
+
+	"github.com/gerald-lbn/refrain/db"
+	"github.com/gerald-lbn/refrain/pkg/migrate"
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Migrate", func() {
+	var (
+		ctx      context.Context
+		database *sql.DB
+	)
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+		database, err = sql.Open("sqlite3", filepath.Join(GinkgoT().TempDir(), "refrain.db"))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(database.Close)
+	})
+
+	tableExists := func(name string) bool {
+		var count int
+		err := database.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count)
+		Expect(err).ToNot(HaveOccurred())
+		return count > 0
+	}
+
+	When("loading migrations", func() {
+		It("should sort them by version and split their sections", func() {
+			migrations, err := migrate.Load(fstest.MapFS{
+				"migrations/2_second.sql": {Data: []byte("-- migrate:up\nCREATE TABLE b (id INTEGER);\n\n-- migrate:down\nDROP TABLE b;\n")},
+				"migrations/1_first.sql":  {Data: []byte("-- migrate:up\nCREATE TABLE a (id INTEGER);\n")},
+				"migrations/README.md":    {Data: []byte("not a migration")},
+			}, "migrations")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(migrations).To(Equal([]migrate.Migration{
+				{Version: 1, Name: "first", Up: "CREATE TABLE a (id INTEGER);"},
+				{Version: 2, Name: "second", Up: "CREATE TABLE b (id INTEGER);", Down: "DROP TABLE b;"},
+			}))
+		})
+
+		It("should read the modules a migration requires", func() {
+			migrations, err := migrate.Load(fstest.MapFS{
+				"migrations/1_index.sql": {Data: []byte("-- migrate:up requires:fts5,rtree\nCREATE TABLE a (id INTEGER);\n")},
+			}, "migrations")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(migrations[0].Requires).To(Equal([]string{"fts5", "rtree"}))
+			Expect(migrations[0].Up).To(Equal("CREATE TABLE a (id INTEGER);"))
+		})
+
+		It("should reject files without version", func() {
+			_, err := migrate.Load(fstest.MapFS{
+				"migrations/create_table.sql": {Data: []byte("-- migrate:up\nCREATE TABLE a (id INTEGER);\n")},
+			}, "migrations")
+			Expect(err).To(MatchError(migrate.ErrInvalidMigration))
+		})
+
+		It("should reject files without up section", func() {
+			_, err := migrate.Load(fstest.MapFS{
+				"migrations/1_empty.sql": {Data: []byte("CREATE TABLE a (id INTEGER);\n")},
+			}, "migrations")
+			Expect(err).To(MatchError(migrate.ErrInvalidMigration))
+		})
+	})
+
+	When("migrating the database", func() {
+		var (
+			migrator   *migrate.Migrator
+			migrations []migrate.Migration
+		)
+
+		BeforeEach(func() {
+			var err error
+			migrations, err = migrate.Load(db.Migrations, "migrations")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(migrations).ToNot(BeEmpty())
+			migrator = migrate.New(database, migrations)
+		})
+
+		It("should apply every pending migration once", func() {
+			applied, err := migrator.Up(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied).ToNot(BeEmpty())
+			Expect(tableExists("tracks")).To(BeTrue())
+
+			applied, err = migrator.Up(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied).To(BeEmpty())
+
+			statuses, err := migrator.Status(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			for i, status := range statuses {
+				// The search index needs SQLite to be built with FTS5
+				if len(migrations[i].Requires) == 0 {
+					Expect(status.Applied).To(BeTrue())
+				}
+			}
+		})
+
+		It("should leave pending the migrations requiring a missing module", func() {
+			migrator = migrate.New(database, []migrate.Migration{
+				{Version: 1, Name: "first", Up: "CREATE TABLE a (id INTEGER);"},
+				{Version: 2, Name: "virtual", Up: "CREATE VIRTUAL TABLE b USING missing(id);", Requires: []string{"missing"}},
+			})
+
+			applied, err := migrator.Up(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied).To(HaveLen(1))
+
+			statuses, err := migrator.Status(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(statuses[1].Applied).To(BeFalse())
+		})
+
+		It("should refuse a schema needing a missing module", func() {
+			migrator = migrate.New(database, []migrate.Migration{
+				{Version: 1, Name: "virtual", Up: "CREATE TABLE b (id INTEGER);", Requires: []string{"missing"}},
+			})
+
+			_, err := database.Exec("CREATE TABLE schema_migrations (version uint64, dirty bool); INSERT INTO schema_migrations VALUES (1, false)")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(migrator.Check(ctx)).To(MatchError(migrate.ErrMissingModule))
+		})
+
+		It("should roll back every migration", func() {
+			applied, err := migrator.Up(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			for range applied {
+				_, err := migrator.Down(ctx)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			Expect(tableExists("tracks")).To(BeFalse())
+
+			_, err = migrator.Down(ctx)
+			Expect(err).To(MatchError(migrate.ErrNothingToRollBack))
+		})
+
+		It("should refuse a schema migrated by a newer version", func() {
+			_, err := migrator.Up(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = database.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (99991231000000, false)")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(migrator.Check(ctx)).To(MatchError(migrate.ErrSchemaTooNew))
+			_, err = migrator.Up(ctx)
+			Expect(err).To(MatchError(migrate.ErrSchemaTooNew))
+		})
+	})
+})
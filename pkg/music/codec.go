@@ -0,0 +1,161 @@
+package music
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var errInvalidContainer = errors.New("invalid audio container")
+
+// mp4Codecs maps the sample entries of MP4 audio tracks to their codec.
+var mp4Codecs = map[string]string{
+	"mp4a": "aac",
+	"alac": "alac",
+	"fLaC": "flac",
+	"Opus": "opus",
+	"ac-3": "ac3",
+	"ec-3": "eac3",
+	".mp3": "mp3",
+}
+
+// mp4ContainerBoxes lists the boxes leading to the sample descriptions of an MP4 file.
+var mp4ContainerBoxes = map[string]bool{
+	"moov": true,
+	"trak": true,
+	"mdia": true,
+	"minf": true,
+	"stbl": true,
+}
+
+// oggCodecs maps the start of the first packet of an Ogg stream to its codec.
+var oggCodecs = []struct {
+	prefix []byte
+	codec  string
+}{
+	{[]byte("\x01vorbis"), "vorbis"},
+	{[]byte("OpusHead"), "opus"},
+	{[]byte("\x7fFLAC"), "flac"},
+	{[]byte("Speex   "), "speex"},
+}
+
+// ReadCodec returns the codec of the audio file at p. Containers holding several codecs, such as
+// MP4 holding AAC or ALAC, are read to find it. Other files are named after their extension, which
+// is also returned along with the error when the container can't be read.
+func ReadCodec(p string) (string, error) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(p), "."))
+
+	var read func(io.ReadSeeker) (string, error)
+	switch ext {
+	case "m4a", "m4b", "mp4":
+		read = readMP4Codec
+	case "ogg", "oga", "opus":
+		read = readOggCodec
+	default:
+		return ext, nil
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	codec, err := read(f)
+	if err != nil || codec == "" {
+		return ext, err
+	}
+	return codec, nil
+}
+
+// readMP4Codec returns the codec of the first audio track of an MP4 file.
+func readMP4Codec(r io.ReadSeeker) (string, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", err
+	}
+	return readMP4Boxes(r, 0, end)
+}
+
+// readMP4Boxes walks the boxes between start and end down to the sample descriptions, and returns
+// the codec of the first audio sample entry found.
+func readMP4Boxes(r io.ReadSeeker, start, end int64) (string, error) {
+	for offset := start; offset+8 <= end; {
+		header := make([]byte, 16)
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return "", err
+		}
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			return "", err
+		}
+
+		size, kind, headerSize := int64(binary.BigEndian.Uint32(header[:4])), string(header[4:8]), int64(8)
+		switch size {
+		case 0:
+			// The box extends to the end of its parent
+			size = end - offset
+		case 1:
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return "", err
+			}
+			size, headerSize = int64(binary.BigEndian.Uint64(header[8:16])), 16
+		}
+		if size < headerSize || offset+size > end {
+			return "", errInvalidContainer
+		}
+
+		switch {
+		case mp4ContainerBoxes[kind]:
+			codec, err := readMP4Boxes(r, offset+headerSize, offset+size)
+			if err != nil || codec != "" {
+				return codec, err
+			}
+		case kind == "stsd":
+			// Version and flags, the number of entries, then the size and type of the first entry
+			description := make([]byte, 16)
+			if _, err := io.ReadFull(r, description); err != nil {
+				return "", err
+			}
+			// Video tracks are skipped
+			if codec, ok := mp4Codecs[string(description[12:16])]; ok {
+				return codec, nil
+			}
+		}
+
+		offset += size
+	}
+
+	return "", nil
+}
+
+// readOggCodec returns the codec of the first stream of an Ogg file, from the first packet of its
+// first page.
+func readOggCodec(r io.ReadSeeker) (string, error) {
+	header := make([]byte, 27)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return "", err
+	}
+	if !bytes.HasPrefix(header, []byte("OggS")) {
+		return "", errInvalidContainer
+	}
+
+	// The segment table follows the header, the packet follows the segment table
+	if _, err := r.Seek(int64(header[26]), io.SeekCurrent); err != nil {
+		return "", err
+	}
+
+	packet := make([]byte, 8)
+	if _, err := io.ReadFull(r, packet); err != nil {
+		return "", err
+	}
+	for _, c := range oggCodecs {
+		if bytes.HasPrefix(packet, c.prefix) {
+			return c.codec, nil
+		}
+	}
+	return "", nil
+}
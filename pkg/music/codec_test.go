@@ -0,0 +1,81 @@
+package music_test
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// mp4Box returns an MP4 box of the given type wrapping the given content.
+func mp4Box(kind string, content ...[]byte) []byte {
+	box := make([]byte, 8)
+	box = append(box[:4], kind...)
+	for _, c := range content {
+		box = append(box, c...)
+	}
+	binary.BigEndian.PutUint32(box, uint32(len(box)))
+	return box
+}
+
+// mp4Track returns the box of a track whose first sample entry has the given type.
+func mp4Track(entry string) []byte {
+	// Version and flags, then a single entry
+	stsd := mp4Box("stsd", []byte{0, 0, 0, 0, 0, 0, 0, 1}, mp4Box(entry, make([]byte, 28)))
+	return mp4Box("trak", mp4Box("mdia", mp4Box("minf", mp4Box("stbl", stsd))))
+}
+
+var _ = Describe("ReadCodec", func() {
+	var dir string
+
+	write := func(name string, data []byte) string {
+		p := filepath.Join(dir, name)
+		Expect(os.WriteFile(p, data, 0644)).To(Succeed())
+		return p
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	It("should tell AAC and ALAC apart in MP4 files", func() {
+		ftyp := mp4Box("ftyp", []byte("M4A \x00\x00\x00\x00"))
+
+		codec, err := music.ReadCodec(write("aac.m4a", append(ftyp, mp4Box("moov", mp4Track("mp4a"))...)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(codec).To(Equal("aac"))
+
+		codec, err = music.ReadCodec(write("alac.m4a", append(ftyp, mp4Box("moov", mp4Track("alac"))...)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(codec).To(Equal("alac"))
+	})
+
+	It("should skip the video tracks of MP4 files", func() {
+		codec, err := music.ReadCodec(write("video.mp4", mp4Box("moov", mp4Track("avc1"), mp4Track("alac"))))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(codec).To(Equal("alac"))
+	})
+
+	It("should read the codec of Ogg files", func() {
+		page := append([]byte("OggS"), make([]byte, 22)...)
+		page = append(page, 1, 19)
+		page = append(page, "OpusHead\x01\x02"...)
+
+		codec, err := music.ReadCodec(write("track.ogg", page))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(codec).To(Equal("opus"))
+	})
+
+	It("should fall back to the extension", func() {
+		codec, err := music.ReadCodec("../test_data/Vore.flac")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(codec).To(Equal("flac"))
+
+		codec, err = music.ReadCodec(write("broken.m4a", []byte("not an mp4 file")))
+		Expect(err).To(HaveOccurred())
+		Expect(codec).To(Equal("m4a"))
+	})
+})
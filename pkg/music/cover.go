@@ -0,0 +1,217 @@
+package music
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.senan.xyz/taglib"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const DEFAULT_COVER_SIZE = 500
+
+var (
+	ErrNoCover = errors.New("no cover art found")
+)
+
+// coverFileNames lists the names of the cover art files looked for in the directory of an audio file,
+// by order of preference.
+var coverFileNames = []string{"cover", "folder", "front", "album"}
+
+// coverFileExtensions lists the extensions of the cover art files which can be decoded.
+var coverFileExtensions = []string{".jpg", ".jpeg", ".png"}
+
+// HasCover indicates whether cover art was found for the audio.
+func (m *Metadata) HasCover() bool {
+	return m.HasEmbeddedCover || m.CoverPath != ""
+}
+
+// ReadCover returns the cover art of the audio, preferring the picture embedded in its tags
+// over a cover file in its directory.
+func ReadCover(m *Metadata) ([]byte, error) {
+	if m.HasEmbeddedCover {
+		data, err := taglib.ReadImage(m.AudioPath)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > 0 {
+			return data, nil
+		}
+	}
+
+	if m.CoverPath != "" {
+		return os.ReadFile(m.CoverPath)
+	}
+
+	return nil, ErrNoCover
+}
+
+// findCoverFile looks for a cover art file such as cover.jpg or folder.jpg in dir, ignoring case.
+// It returns an empty string if there is none.
+func findCoverFile(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	found := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if !slices.Contains(coverFileExtensions, ext) {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))
+		if _, ok := found[name]; !ok {
+			found[name] = filepath.Join(dir, entry.Name())
+		}
+	}
+
+	for _, name := range coverFileNames {
+		if p, ok := found[name]; ok {
+			return p
+		}
+	}
+	return ""
+}
+
+// CoverCache stores resized cover art on disk so it is only decoded once per audio file.
+type CoverCache struct {
+	// Directory holds the cached thumbnails
+	Directory string
+	// Size is the maximum width and height of the thumbnails, DEFAULT_COVER_SIZE when zero
+	Size int
+}
+
+// size returns the maximum width and height of the thumbnails.
+func (c CoverCache) size() int {
+	if c.Size <= 0 {
+		return DEFAULT_COVER_SIZE
+	}
+	return c.Size
+}
+
+// path returns the path of the thumbnail of the audio file at audioPath. Tracks of a CUE sheet share
+// the cover art of their audio file.
+func (c CoverCache) path(audioPath string) string {
+	sum := sha1.Sum([]byte(audioPath))
+	return filepath.Join(c.Directory, fmt.Sprintf("%s-%d.jpg", hex.EncodeToString(sum[:]), c.size()))
+}
+
+// Cached returns the path of the thumbnail cached for the audio file at audioPath, without reading
+// the file, when it is not older than the file. A changed cover file is only noticed by Thumbnail.
+func (c CoverCache) Cached(audioPath string) (string, bool) {
+	audioInfo, err := os.Stat(audioPath)
+	if err != nil {
+		return "", false
+	}
+
+	p := c.path(audioPath)
+	if info, err := os.Stat(p); err != nil || info.ModTime().Before(audioInfo.ModTime()) {
+		return "", false
+	}
+	return p, true
+}
+
+// Thumbnail returns the path of the cached thumbnail of the cover art of the audio,
+// creating it if it is missing or older than its source.
+func (c CoverCache) Thumbnail(m *Metadata) (string, error) {
+	if !m.HasCover() {
+		return "", ErrNoCover
+	}
+
+	source := m.CoverPath
+	if m.HasEmbeddedCover {
+		source = m.AudioPath
+	}
+	sourceInfo, err := os.Stat(source)
+	if err != nil {
+		return "", err
+	}
+
+	p := c.path(m.AudioPath)
+	if info, err := os.Stat(p); err == nil && !info.ModTime().Before(sourceInfo.ModTime()) {
+		return p, nil
+	}
+
+	data, err := ReadCover(m)
+	if err != nil {
+		return "", err
+	}
+
+	thumbnail, err := ResizeCover(data, c.size())
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(c.Directory, 0755); err != nil {
+		return "", err
+	}
+
+	// Write to a temporary file first so concurrent readers never see a partial thumbnail
+	tmp, err := os.CreateTemp(c.Directory, ".cover-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(thumbnail); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return "", err
+	}
+	return p, nil
+}
+
+// ResizeCover scales a JPEG, PNG, GIF or WebP picture down to fit in a size x size square, keeping
+// its aspect ratio, and encodes it as JPEG. Smaller pictures are re-encoded without being enlarged.
+// Pictures in other formats are reported as ErrNoCover.
+func ResizeCover(data []byte, size int) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, fmt.Errorf("%w: unsupported picture format", ErrNoCover)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to decode cover art: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, max(1, height*size/width)
+		} else {
+			width, height = max(1, width*size/height), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
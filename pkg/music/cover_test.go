@@ -0,0 +1,150 @@
+package music_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.senan.xyz/taglib"
+)
+
+// encodePicture returns a PNG picture of the given size.
+func encodePicture(width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		for y := range height {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	Expect(png.Encode(&buf, img)).To(Succeed())
+	return buf.Bytes()
+}
+
+var _ = Describe("Cover", func() {
+	var dir string
+	var audioPath string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		audioPath = copyFile("../test_data/Vore.flac", dir)
+	})
+
+	When("the audio has no cover art", func() {
+		It("should not report any cover", func() {
+			metadata, err := music.ExtractMetadata(audioPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metadata.HasCover()).To(BeFalse())
+
+			_, err = music.ReadCover(metadata)
+			Expect(err).To(MatchError(music.ErrNoCover))
+		})
+	})
+
+	When("the album directory has a cover file", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(filepath.Join(dir, "Folder.png"), encodePicture(40, 20), 0644)).To(Succeed())
+		})
+
+		It("should find it regardless of case", func() {
+			metadata, err := music.ExtractMetadata(audioPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metadata.HasEmbeddedCover).To(BeFalse())
+			Expect(metadata.CoverPath).To(Equal(filepath.Join(dir, "Folder.png")))
+		})
+
+		It("should cache a resized thumbnail", func() {
+			metadata, err := music.ExtractMetadata(audioPath)
+			Expect(err).ToNot(HaveOccurred())
+
+			cache := music.CoverCache{Directory: filepath.Join(dir, "cache"), Size: 10}
+			p, err := cache.Thumbnail(metadata)
+			Expect(err).ToNot(HaveOccurred())
+
+			f, err := os.Open(p)
+			Expect(err).ToNot(HaveOccurred())
+			defer f.Close()
+
+			config, err := jpeg.DecodeConfig(f)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.Width).To(Equal(10))
+			Expect(config.Height).To(Equal(5))
+
+			again, err := cache.Thumbnail(metadata)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(again).To(Equal(p))
+
+			cached, ok := cache.Cached(metadata.AudioPath)
+			Expect(ok).To(BeTrue())
+			Expect(cached).To(Equal(p))
+		})
+
+		It("should not report thumbnails which were never cached", func() {
+			cache := music.CoverCache{Directory: filepath.Join(dir, "cache"), Size: 10}
+			_, ok := cache.Cached(audioPath)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	When("the audio has an embedded picture", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(filepath.Join(dir, "cover.png"), encodePicture(8, 8), 0644)).To(Succeed())
+			Expect(taglib.WriteImage(audioPath, encodePicture(16, 16))).To(Succeed())
+		})
+
+		It("should prefer it over the cover file", func() {
+			metadata, err := music.ExtractMetadata(audioPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metadata.HasEmbeddedCover).To(BeTrue())
+
+			data, err := music.ReadCover(metadata)
+			Expect(err).ToNot(HaveOccurred())
+
+			config, _, err := image.DecodeConfig(bytes.NewReader(data))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.Width).To(Equal(16))
+		})
+	})
+
+	When("resizing a picture in an unsupported format", func() {
+		It("should report it as no cover", func() {
+			_, err := music.ResizeCover([]byte("BM not really a bitmap"), 100)
+			Expect(err).To(MatchError(music.ErrNoCover))
+		})
+	})
+
+	When("resizing a GIF picture", func() {
+		It("should decode it", func() {
+			var buf bytes.Buffer
+			Expect(gif.Encode(&buf, image.NewPaletted(image.Rect(0, 0, 20, 10), color.Palette{color.Black}), nil)).To(Succeed())
+
+			data, err := music.ResizeCover(buf.Bytes(), 100)
+			Expect(err).ToNot(HaveOccurred())
+
+			config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.Width).To(Equal(20))
+		})
+	})
+
+	When("resizing a small picture", func() {
+		It("should not enlarge it", func() {
+			data, err := music.ResizeCover(encodePicture(20, 30), 100)
+			Expect(err).ToNot(HaveOccurred())
+
+			config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.Width).To(Equal(20))
+			Expect(config.Height).To(Equal(30))
+		})
+	})
+})
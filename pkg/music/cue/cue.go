@@ -0,0 +1,245 @@
+package cue
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FRAMES_PER_SECOND is the number of CD frames in a second, the unit of CUE sheet indexes.
+const FRAMES_PER_SECOND = 75
+
+var (
+	ErrNoTracks       = errors.New("cue sheet has no tracks")
+	ErrInvalidIndex   = errors.New("invalid cue sheet index")
+	ErrTrackNotInFile = errors.New("cue sheet track is not part of a file")
+)
+
+// Sheet is a parsed CUE sheet.
+type Sheet struct {
+	// Performer is the performer of the whole disc
+	Performer string
+	// Title is the title of the disc
+	Title string
+	// Date is the release date found in a REM DATE comment
+	Date string
+	// Genre is the genre found in a REM GENRE comment
+	Genre string
+	// Tracks lists the tracks in the order they appear
+	Tracks []Track
+}
+
+// Track is a track of a CUE sheet.
+type Track struct {
+	// Number is the track number
+	Number int
+	// Title is the title of the track
+	Title string
+	// Performer is the performer of the track, empty when it is the disc performer
+	Performer string
+	// ISRC is the International Standard Recording Code of the track
+	ISRC string
+	// File is the audio file holding the track, as written in the sheet
+	File string
+	// Start is the position of the track in its file, from INDEX 01
+	Start time.Duration
+	// End is the position of the next track of the same file, zero for the last track of a file
+	End time.Duration
+}
+
+// ParseFile parses the CUE sheet at p. Track files are made absolute relative to the sheet directory.
+func ParseFile(p string) (*Sheet, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sheet, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+
+	dir := filepath.Dir(p)
+	for i := range sheet.Tracks {
+		if !filepath.IsAbs(sheet.Tracks[i].File) {
+			sheet.Tracks[i].File = filepath.Join(dir, filepath.FromSlash(sheet.Tracks[i].File))
+		}
+	}
+	return sheet, nil
+}
+
+// Parse parses a CUE sheet. Unknown commands are ignored.
+func Parse(r io.Reader) (*Sheet, error) {
+	sheet := &Sheet{}
+
+	var file string
+	var track *Track
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+
+		command, args := splitCommand(line)
+		switch command {
+		case "FILE":
+			if len(args) > 0 {
+				file = args[0]
+			}
+		case "TRACK":
+			if len(args) == 0 {
+				continue
+			}
+			number, err := strconv.Atoi(args[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid track number %q", lineNumber, args[0])
+			}
+			if file == "" {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, ErrTrackNotInFile)
+			}
+			sheet.Tracks = append(sheet.Tracks, Track{Number: number, File: file, Start: -1})
+			track = &sheet.Tracks[len(sheet.Tracks)-1]
+		case "TITLE":
+			if track != nil {
+				track.Title = first(args)
+			} else {
+				sheet.Title = first(args)
+			}
+		case "PERFORMER":
+			if track != nil {
+				track.Performer = first(args)
+			} else {
+				sheet.Performer = first(args)
+			}
+		case "ISRC":
+			if track != nil {
+				track.ISRC = first(args)
+			}
+		case "INDEX":
+			if track == nil || len(args) < 2 || args[0] != "01" {
+				continue
+			}
+			start, err := parseIndex(args[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			track.Start = start
+		case "REM":
+			if len(args) < 2 {
+				continue
+			}
+			switch strings.ToUpper(args[0]) {
+			case "DATE":
+				sheet.Date = args[1]
+			case "GENRE":
+				sheet.Genre = args[1]
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(sheet.Tracks) == 0 {
+		return nil, ErrNoTracks
+	}
+
+	for i := range sheet.Tracks {
+		if sheet.Tracks[i].Start < 0 {
+			return nil, fmt.Errorf("track %d: %w: missing INDEX 01", sheet.Tracks[i].Number, ErrInvalidIndex)
+		}
+		if i+1 < len(sheet.Tracks) && sheet.Tracks[i+1].File == sheet.Tracks[i].File {
+			sheet.Tracks[i].End = sheet.Tracks[i+1].Start
+		}
+	}
+
+	return sheet, nil
+}
+
+// Track returns the track with the given number, or false if the sheet has none.
+func (s *Sheet) Track(number int) (Track, bool) {
+	for _, track := range s.Tracks {
+		if track.Number == number {
+			return track, true
+		}
+	}
+	return Track{}, false
+}
+
+// splitCommand splits a line into its upper-cased command and its arguments,
+// honouring double-quoted arguments.
+func splitCommand(line string) (string, []string) {
+	var fields []string
+	for line != "" {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			break
+		}
+
+		if line[0] == '"' {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				fields = append(fields, line[1:])
+				break
+			}
+			fields = append(fields, line[1:end+1])
+			line = line[end+2:]
+			continue
+		}
+
+		end := strings.IndexAny(line, " \t")
+		if end < 0 {
+			fields = append(fields, line)
+			break
+		}
+		fields = append(fields, line[:end])
+		line = line[end:]
+	}
+
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return strings.ToUpper(fields[0]), fields[1:]
+}
+
+// parseIndex parses an index position written as mm:ss:ff, ff being CD frames.
+func parseIndex(value string) (time.Duration, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("%w %q", ErrInvalidIndex, value)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%w %q", ErrInvalidIndex, value)
+		}
+		numbers[i] = n
+	}
+
+	minutes, seconds, frames := numbers[0], numbers[1], numbers[2]
+	if seconds >= 60 || frames >= FRAMES_PER_SECOND {
+		return 0, fmt.Errorf("%w %q", ErrInvalidIndex, value)
+	}
+
+	return time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second +
+		time.Duration(frames)*time.Second/FRAMES_PER_SECOND, nil
+}
+
+// first returns the first argument, or an empty string if there is none.
+func first(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
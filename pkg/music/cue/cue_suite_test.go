@@ -0,0 +1,13 @@
+package cue_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCue(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cue Suite")
+}
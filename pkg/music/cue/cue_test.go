@@ -0,0 +1,89 @@
+package cue_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/music/cue"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const sheet = "\uFEFF" + `REM GENRE Classical
+REM DATE 1999
+PERFORMER "Berliner Philharmoniker"
+TITLE "Symphony No. 5"
+FILE "Symphony.wav" WAVE
+  TRACK 01 AUDIO
+    TITLE "I. Allegro con brio"
+    ISRC DEF129900001
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    TITLE "II. Andante con moto"
+    PERFORMER "Herbert von Karajan"
+    INDEX 00 07:30:00
+    INDEX 01 07:32:37
+FILE "Bonus.flac" WAVE
+  TRACK 03 AUDIO
+    TITLE "Rehearsal"
+    INDEX 01 00:00:00
+`
+
+var _ = Describe("Cue", func() {
+	When("parsing a cue sheet", func() {
+		It("should read the disc and its tracks", func() {
+			s, err := cue.Parse(strings.NewReader(sheet))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.Performer).To(Equal("Berliner Philharmoniker"))
+			Expect(s.Title).To(Equal("Symphony No. 5"))
+			Expect(s.Date).To(Equal("1999"))
+			Expect(s.Genre).To(Equal("Classical"))
+			Expect(s.Tracks).To(HaveLen(3))
+
+			first := s.Tracks[0]
+			Expect(first.Number).To(Equal(1))
+			Expect(first.Title).To(Equal("I. Allegro con brio"))
+			Expect(first.Performer).To(BeEmpty())
+			Expect(first.ISRC).To(Equal("DEF129900001"))
+			Expect(first.File).To(Equal("Symphony.wav"))
+			Expect(first.Start).To(BeZero())
+			Expect(first.End).To(Equal(7*time.Minute + 32*time.Second + 37*time.Second/75))
+		})
+
+		It("should use INDEX 01 as the start of a track", func() {
+			s, err := cue.Parse(strings.NewReader(sheet))
+			Expect(err).ToNot(HaveOccurred())
+
+			second, ok := s.Track(2)
+			Expect(ok).To(BeTrue())
+			Expect(second.Performer).To(Equal("Herbert von Karajan"))
+			Expect(second.Start).To(Equal(7*time.Minute + 32*time.Second + 37*time.Second/75))
+			Expect(second.End).To(BeZero())
+		})
+
+		It("should not end a track at the start of another file", func() {
+			s, err := cue.Parse(strings.NewReader(sheet))
+			Expect(err).ToNot(HaveOccurred())
+
+			third, ok := s.Track(3)
+			Expect(ok).To(BeTrue())
+			Expect(third.File).To(Equal("Bonus.flac"))
+			Expect(third.End).To(BeZero())
+		})
+
+		It("should reject sheets without tracks", func() {
+			_, err := cue.Parse(strings.NewReader(`TITLE "Empty"`))
+			Expect(err).To(MatchError(cue.ErrNoTracks))
+		})
+
+		It("should reject invalid indexes", func() {
+			_, err := cue.Parse(strings.NewReader("FILE \"a.flac\" WAVE\nTRACK 01 AUDIO\nINDEX 01 00:00:80\n"))
+			Expect(err).To(MatchError(cue.ErrInvalidIndex))
+		})
+
+		It("should reject tracks without a file", func() {
+			_, err := cue.Parse(strings.NewReader("TRACK 01 AUDIO\nINDEX 01 00:00:00\n"))
+			Expect(err).To(MatchError(cue.ErrTrackNotInFile))
+		})
+	})
+})
@@ -0,0 +1,236 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/music/cue"
+	"github.com/gerald-lbn/refrain/pkg/utils/file"
+)
+
+const CUE_SHEET_EXTENSION = "cue"
+
+// DEFAULT_CUE_LYRICS_PATH_TEMPLATE names the lyrics of the tracks of a CUE sheet after their artist
+// and title, which is what CUE-aware players look for since the tracks share a single audio file.
+const DEFAULT_CUE_LYRICS_PATH_TEMPLATE = "{dir}/{artist} - {title}.{ext}"
+
+var (
+	ErrNotInCueSheet = errors.New("track not found in the cue sheet of the audio file")
+)
+
+// VirtualTrackPath returns the path identifying a track of a CUE sheet: the path of the audio file
+// holding the track followed by "#" and the track number.
+func VirtualTrackPath(audio string, number int) string {
+	return fmt.Sprintf("%s#%02d", audio, number)
+}
+
+// ParseVirtualTrackPath returns the audio file and the track number identified by a virtual track path,
+// or false if p is not one.
+func ParseVirtualTrackPath(p string) (string, int, bool) {
+	i := strings.LastIndexByte(p, '#')
+	if i < 0 {
+		return "", 0, false
+	}
+
+	number, err := strconv.Atoi(p[i+1:])
+	if err != nil || number <= 0 {
+		return "", 0, false
+	}
+
+	// A file may legitimately be named like a virtual track
+	if file.Exists(p) {
+		return "", 0, false
+	}
+
+	return p[:i], number, true
+}
+
+// AudioFilePath returns the file holding the audio at p, which differs from p for tracks of a CUE sheet.
+func AudioFilePath(p string) string {
+	if audio, _, ok := ParseVirtualTrackPath(p); ok {
+		return audio
+	}
+	return p
+}
+
+// IsCueSheet indicates whether p is a CUE sheet, based on its extension.
+func IsCueSheet(p string) bool {
+	return strings.EqualFold(filepath.Ext(p), "."+CUE_SHEET_EXTENSION)
+}
+
+// IsVirtual indicates whether the audio is a track of a CUE sheet rather than a whole file.
+func (m *Metadata) IsVirtual() bool {
+	return m.CueSheetPath != ""
+}
+
+// FindCueSheet looks in the directory of an audio file for a CUE sheet splitting it into several tracks.
+// Sheets sharing the base name of the audio file are preferred. It returns an empty path if there is none.
+func FindCueSheet(audio string) (string, *cue.Sheet, error) {
+	dir := filepath.Dir(audio)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var candidates []string
+	stem := strings.TrimSuffix(filepath.Base(audio), filepath.Ext(audio))
+	for _, entry := range entries {
+		if entry.IsDir() || !IsCueSheet(entry.Name()) {
+			continue
+		}
+
+		p := filepath.Join(dir, entry.Name())
+		if strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())) == stem {
+			candidates = append([]string{p}, candidates...)
+		} else {
+			candidates = append(candidates, p)
+		}
+	}
+
+	for _, p := range candidates {
+		sheet, err := cue.ParseFile(p)
+		if err != nil {
+			continue
+		}
+
+		if len(tracksOfFile(sheet, audio)) > 1 {
+			return p, sheet, nil
+		}
+	}
+
+	return "", nil, nil
+}
+
+// HasCueSheet indicates whether p is an audio file split into several tracks by a CUE sheet,
+// in which case its tracks are handled instead of the whole file.
+func HasCueSheet(p string) bool {
+	if _, _, ok := ParseVirtualTrackPath(p); ok {
+		return false
+	}
+
+	sheetPath, _, err := FindCueSheet(p)
+	return err == nil && sheetPath != ""
+}
+
+// CueSheetTracks returns the virtual track paths of the tracks described by a CUE sheet,
+// for each of its audio files holding several tracks.
+func CueSheetTracks(p string) ([]string, error) {
+	sheet, err := cue.ParseFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, track := range sheet.Tracks {
+		if seen[track.File] {
+			continue
+		}
+		seen[track.File] = true
+
+		audio := resolveCueFile(track.File)
+		if audio == "" {
+			continue
+		}
+
+		tracks := tracksOfFile(sheet, audio)
+		if len(tracks) < 2 {
+			continue
+		}
+		for _, t := range tracks {
+			paths = append(paths, VirtualTrackPath(audio, t.Number))
+		}
+	}
+	return paths, nil
+}
+
+// tracksOfFile returns the tracks of the sheet held by the audio file.
+func tracksOfFile(sheet *cue.Sheet, audio string) []cue.Track {
+	var tracks []cue.Track
+	for _, track := range sheet.Tracks {
+		if resolveCueFile(track.File) == filepath.Clean(audio) {
+			tracks = append(tracks, track)
+		}
+	}
+	return tracks
+}
+
+// resolveCueFile returns the audio file referenced by a CUE sheet. Sheets often keep referencing
+// the original WAV file after it was converted, so a file with the same name and another extension
+// is accepted as well. It returns an empty string if no file is found.
+func resolveCueFile(p string) string {
+	p = filepath.Clean(p)
+	if file.Exists(p) {
+		return p
+	}
+
+	dir := filepath.Dir(p)
+	stem := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || IsCueSheet(name) || strings.TrimSuffix(name, filepath.Ext(name)) != stem {
+			continue
+		}
+
+		if isAudio, err := file.IsAudioFile(filepath.Join(dir, name)); err == nil && isAudio {
+			return filepath.Join(dir, name)
+		}
+	}
+	return ""
+}
+
+// applyCueTrack overrides the metadata read from the tags of the whole file with the ones of a track
+// of its CUE sheet. length is the duration of the whole file.
+func (m *Metadata) applyCueTrack(sheetPath string, sheet *cue.Sheet, track cue.Track, length time.Duration) {
+	m.CueSheetPath = sheetPath
+	m.Start = track.Start.Seconds()
+
+	end := track.End
+	if end == 0 || end > length {
+		end = length
+	}
+	m.Duration = max(0, (end - track.Start).Seconds())
+
+	set := func(value **string, values ...string) {
+		for _, v := range values {
+			if v != "" {
+				*value = &v
+				return
+			}
+		}
+	}
+	set(&m.Title, track.Title)
+	if track.Performer != "" || sheet.Performer != "" {
+		// The artists of the whole file are replaced by the performer of the track
+		m.Artists = nil
+		m.MusicBrainzArtistID = ""
+	}
+	set(&m.Artist, track.Performer, sheet.Performer)
+	set(&m.AlbumArtist, sheet.Performer)
+	set(&m.Album, sheet.Title)
+
+	m.TrackNumber = track.Number
+	if sheet.Date != "" {
+		m.ReleaseDate = sheet.Date
+		m.Year = parseYear(sheet.Date)
+	}
+	if sheet.Genre != "" {
+		m.Genre = sheet.Genre
+	}
+
+	// Identifiers and lyrics found in the tags describe the whole file
+	m.ISRC = track.ISRC
+	m.MusicBrainzRecordingID = ""
+	m.EmbeddedPlainLyrics = ""
+	m.EmbeddedSyncedLyrics = ""
+}
@@ -0,0 +1,102 @@
+package music_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CUE sheets", func() {
+	var dir string
+	var audioPath string
+	var sheetPath string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		audioPath = filepath.Join(dir, "Live.flac")
+		Expect(os.Rename(copyFile("../test_data/Vore.flac", dir), audioPath)).To(Succeed())
+
+		// The sheet still references the WAV file the album was ripped to
+		sheetPath = filepath.Join(dir, "Live.cue")
+		Expect(os.WriteFile(sheetPath, []byte(`PERFORMER "Sleep Token"
+TITLE "Live at Wembley"
+REM DATE 2024
+FILE "Live.wav" WAVE
+  TRACK 01 AUDIO
+    TITLE "Vore"
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    TITLE "Aqua Regia"
+    INDEX 01 00:02:00
+`), 0644)).To(Succeed())
+	})
+
+	It("should list the tracks of a sheet", func() {
+		paths, err := music.CueSheetTracks(sheetPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(paths).To(Equal([]string{
+			music.VirtualTrackPath(audioPath, 1),
+			music.VirtualTrackPath(audioPath, 2),
+		}))
+	})
+
+	It("should find the sheet of an audio file", func() {
+		p, sheet, err := music.FindCueSheet(audioPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p).To(Equal(sheetPath))
+		Expect(sheet.Tracks).To(HaveLen(2))
+		Expect(music.HasCueSheet(audioPath)).To(BeTrue())
+	})
+
+	It("should tell virtual track paths apart", func() {
+		p := music.VirtualTrackPath(audioPath, 2)
+		audio, number, ok := music.ParseVirtualTrackPath(p)
+		Expect(ok).To(BeTrue())
+		Expect(audio).To(Equal(audioPath))
+		Expect(number).To(Equal(2))
+		Expect(music.AudioFilePath(p)).To(Equal(audioPath))
+		Expect(music.HasCueSheet(p)).To(BeFalse())
+
+		_, _, ok = music.ParseVirtualTrackPath(audioPath)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should extract the metadata of a track from the sheet", func() {
+		metadata, err := music.ExtractMetadata(music.VirtualTrackPath(audioPath, 2))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metadata.IsVirtual()).To(BeTrue())
+		Expect(metadata.AudioPath).To(Equal(audioPath))
+		Expect(metadata.CueSheetPath).To(Equal(sheetPath))
+		Expect(*metadata.Title).To(Equal("Aqua Regia"))
+		Expect(*metadata.Artist).To(Equal("Sleep Token"))
+		Expect(*metadata.Album).To(Equal("Live at Wembley"))
+		Expect(metadata.TrackNumber).To(Equal(2))
+		Expect(metadata.Year).To(Equal(2024))
+		Expect(metadata.ISRC).To(BeEmpty())
+		Expect(metadata.Start).To(BeNumerically("==", 2))
+		Expect(metadata.Duration).To(BeNumerically("~", 2.6, 0.1))
+		Expect(metadata.SyncedLyricsPath).To(Equal(filepath.Join(dir, "Sleep Token - Aqua Regia.lrc")))
+	})
+
+	It("should end a track at the start of the next one", func() {
+		metadata, err := music.ExtractMetadata(music.VirtualTrackPath(audioPath, 1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metadata.Start).To(BeZero())
+		Expect(metadata.Duration).To(BeNumerically("==", 2))
+	})
+
+	It("should fail for tracks missing from the sheet", func() {
+		_, err := music.ExtractMetadata(music.VirtualTrackPath(audioPath, 3))
+		Expect(err).To(MatchError(music.ErrNotInCueSheet))
+	})
+
+	It("should use the configured lyrics path for the tracks", func() {
+		template := music.LyricsPathTemplate{CueTemplate: "{dir}/{basename} - {track}.{ext}"}
+		metadata, err := music.ExtractMetadata(music.VirtualTrackPath(audioPath, 1), music.WithLyricsPathTemplate(template))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metadata.PlainLyricsPath).To(Equal(filepath.Join(dir, "Live - 01.txt")))
+	})
+})
@@ -0,0 +1,198 @@
+package music
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Encoding is the character encoding of a lyrics file.
+type Encoding string
+
+const (
+	EncodingUTF8        Encoding = "utf-8"
+	EncodingUTF8BOM     Encoding = "utf-8-bom"
+	EncodingUTF16LE     Encoding = "utf-16le"
+	EncodingUTF16BE     Encoding = "utf-16be"
+	EncodingShiftJIS    Encoding = "shift-jis"
+	EncodingWindows1252 Encoding = "windows-1252"
+)
+
+// LineEnding is the line terminator lyrics files are written with.
+type LineEnding string
+
+const (
+	LineEndingLF   LineEnding = "lf"
+	LineEndingCRLF LineEnding = "crlf"
+)
+
+var (
+	ErrInvalidLineEnding = errors.New("line ending must be either lf or crlf")
+	// ErrUncertainEncoding is returned when lyrics don't look like any encoding that can be told
+	// apart reliably, such as Windows-1251 or GBK text, so converting them would garble them.
+	ErrUncertainEncoding = errors.New("encoding of the lyrics can't be detected reliably")
+)
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// ParseLineEnding returns the line ending with the given name, case-insensitively.
+func ParseLineEnding(name string) (LineEnding, error) {
+	ending := LineEnding(strings.ToLower(name))
+	if ending != LineEndingLF && ending != LineEndingCRLF {
+		return "", ErrInvalidLineEnding
+	}
+	return ending, nil
+}
+
+// IsLyricsFile reports whether p has the extension of a synced or plain lyrics file.
+func IsLyricsFile(p string) bool {
+	ext := strings.TrimPrefix(filepath.Ext(p), ".")
+	return strings.EqualFold(ext, SYNCED_LYRICS_EXTENSION) || strings.EqualFold(ext, PLAIN_LYRICS_EXTENSION)
+}
+
+// DetectEncoding guesses the character encoding of data.
+// Byte order marks are trusted first, then valid UTF-8 is assumed. Shift-JIS is only chosen when
+// the text decodes cleanly to kana, since Japanese lyrics are hardly ever written without them and
+// most Windows-1252 text happens to be valid Shift-JIS as well. Anything else is Windows-1252.
+func DetectEncoding(data []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(data, utf8BOM):
+		return EncodingUTF8BOM
+	case bytes.HasPrefix(data, utf16LEBOM):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(data, utf16BEBOM):
+		return EncodingUTF16BE
+	case utf8.Valid(data):
+		return EncodingUTF8
+	case isShiftJIS(data):
+		return EncodingShiftJIS
+	default:
+		return EncodingWindows1252
+	}
+}
+
+// isShiftJIS reports whether data decodes as Shift-JIS without errors and contains kana.
+func isShiftJIS(data []byte) bool {
+	decoded, err := japanese.ShiftJIS.NewDecoder().Bytes(data)
+	if err != nil || bytes.ContainsRune(decoded, utf8.RuneError) {
+		return false
+	}
+
+	return bytes.ContainsFunc(decoded, func(r rune) bool {
+		// Hiragana and full-width katakana, half-width katakana overlap with accented latin letters
+		return r >= 0x3040 && r <= 0x30FF
+	})
+}
+
+// isWindows1252 reports whether data looks like Windows-1252 text rather than another single or
+// double byte encoding. Accented letters and typographic punctuation are scattered in Western text,
+// while Cyrillic, Greek or Chinese text is made of long runs of bytes above 0x7F. Bytes left
+// undefined by Windows-1252 rule it out.
+func isWindows1252(data []byte) bool {
+	run := 0
+	for _, b := range data {
+		switch {
+		case b == 0x81 || b == 0x8D || b == 0x8F || b == 0x90 || b == 0x9D:
+			return false
+		case b >= 0x80:
+			if run++; run > 2 {
+				return false
+			}
+		default:
+			run = 0
+		}
+	}
+	return true
+}
+
+// decoder returns the decoder converting text in the given encoding to UTF-8.
+func (e Encoding) decoder() *encoding.Decoder {
+	switch e {
+	case EncodingUTF8BOM:
+		return unicode.UTF8BOM.NewDecoder()
+	case EncodingUTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder()
+	case EncodingUTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder()
+	case EncodingShiftJIS:
+		return japanese.ShiftJIS.NewDecoder()
+	case EncodingWindows1252:
+		return charmap.Windows1252.NewDecoder()
+	default:
+		return encoding.Nop.NewDecoder()
+	}
+}
+
+// NormalizeText converts lyrics to UTF-8 without byte order mark, using the given line ending.
+// It returns the normalized text and the encoding detected in data, or ErrUncertainEncoding when
+// the text is neither Unicode, Shift-JIS nor likely Windows-1252.
+func NormalizeText(data []byte, ending LineEnding) ([]byte, Encoding, error) {
+	if ending != LineEndingLF && ending != LineEndingCRLF {
+		return nil, "", ErrInvalidLineEnding
+	}
+
+	enc := DetectEncoding(data)
+	if enc == EncodingWindows1252 && !isWindows1252(data) {
+		return nil, enc, ErrUncertainEncoding
+	}
+
+	decoded, err := enc.decoder().Bytes(data)
+	if err != nil {
+		return nil, enc, err
+	}
+
+	// A BOM may remain when a UTF-8 file was saved twice by a confused editor
+	decoded = bytes.TrimPrefix(decoded, utf8BOM)
+
+	decoded = bytes.ReplaceAll(decoded, []byte("\r\n"), []byte("\n"))
+	decoded = bytes.ReplaceAll(decoded, []byte("\r"), []byte("\n"))
+	if ending == LineEndingCRLF {
+		decoded = bytes.ReplaceAll(decoded, []byte("\n"), []byte("\r\n"))
+	}
+
+	return decoded, enc, nil
+}
+
+// NormalizeLyricsFile rewrites the lyrics file at p as UTF-8 without byte order mark, using the
+// given line ending. The file is left untouched when it is already normalized, or when its encoding
+// is uncertain, in which case ErrUncertainEncoding is returned.
+// It returns the encoding detected in the file and whether the file was rewritten.
+func NormalizeLyricsFile(p string, ending LineEnding) (Encoding, bool, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return "", false, err
+	}
+
+	normalized, enc, err := NormalizeText(data, ending)
+	if err != nil {
+		return enc, false, err
+	}
+
+	if bytes.Equal(normalized, data) {
+		return enc, false, nil
+	}
+
+	info, err := os.Stat(p)
+	if err != nil {
+		return enc, false, err
+	}
+
+	// The file is written in place, renaming a temporary file would be seen as a deletion by the watcher
+	if err := os.WriteFile(p, normalized, info.Mode().Perm()); err != nil {
+		return enc, false, err
+	}
+
+	return enc, true, nil
+}
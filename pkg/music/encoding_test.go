@@ -0,0 +1,139 @@
+package music_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+var _ = Describe("Encoding", func() {
+	mustEncode := func(b []byte, err error) []byte {
+		Expect(err).ToNot(HaveOccurred())
+		return b
+	}
+
+	When("detecting the encoding of lyrics", func() {
+		DescribeTable("should recognize the encoding",
+			func(data func() []byte, expected music.Encoding) {
+				Expect(music.DetectEncoding(data())).To(Equal(expected))
+			},
+			Entry("plain UTF-8", func() []byte { return []byte("Café Müller") }, music.EncodingUTF8),
+			Entry("UTF-8 with BOM", func() []byte { return []byte("\uFEFFCafé") }, music.EncodingUTF8BOM),
+			Entry("UTF-16LE with BOM", func() []byte {
+				return mustEncode(unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte("Café")))
+			}, music.EncodingUTF16LE),
+			Entry("UTF-16BE with BOM", func() []byte {
+				return mustEncode(unicode.UTF16(unicode.BigEndian, unicode.UseBOM).NewEncoder().Bytes([]byte("Café")))
+			}, music.EncodingUTF16BE),
+			Entry("Shift-JIS", func() []byte {
+				return mustEncode(japanese.ShiftJIS.NewEncoder().Bytes([]byte("[00:01.00]夜に駆ける")))
+			}, music.EncodingShiftJIS),
+			Entry("Windows-1252", func() []byte {
+				return mustEncode(charmap.Windows1252.NewEncoder().Bytes([]byte("Don’t say élan, café")))
+			}, music.EncodingWindows1252),
+		)
+	})
+
+	When("normalizing lyrics", func() {
+		It("should convert to UTF-8 with LF line endings", func() {
+			data := mustEncode(unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte("Line 1\r\nLine 2\rLine 3\n")))
+
+			normalized, enc, err := music.NormalizeText(data, music.LineEndingLF)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(enc).To(Equal(music.EncodingUTF16LE))
+			Expect(string(normalized)).To(Equal("Line 1\nLine 2\nLine 3\n"))
+		})
+
+		It("should use CRLF line endings when asked", func() {
+			data := mustEncode(charmap.Windows1252.NewEncoder().Bytes([]byte("Café\nMüller\n")))
+
+			normalized, _, err := music.NormalizeText(data, music.LineEndingCRLF)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(normalized)).To(Equal("Café\r\nMüller\r\n"))
+		})
+
+		DescribeTable("should refuse text whose encoding is uncertain",
+			func(data func() []byte) {
+				_, enc, err := music.NormalizeText(data(), music.LineEndingLF)
+				Expect(err).To(MatchError(music.ErrUncertainEncoding))
+				Expect(enc).To(Equal(music.EncodingWindows1252))
+			},
+			Entry("Windows-1251", func() []byte {
+				return mustEncode(charmap.Windows1251.NewEncoder().Bytes([]byte("Привет, мир")))
+			}),
+			Entry("GBK", func() []byte {
+				return mustEncode(simplifiedchinese.GBK.NewEncoder().Bytes([]byte("月亮代表我的心")))
+			}),
+			Entry("Big5", func() []byte {
+				return mustEncode(traditionalchinese.Big5.NewEncoder().Bytes([]byte("月亮代表我的心")))
+			}),
+		)
+
+		It("should reject unknown line endings", func() {
+			_, _, err := music.NormalizeText([]byte("Song"), "cr")
+			Expect(err).To(MatchError(music.ErrInvalidLineEnding))
+		})
+	})
+
+	When("normalizing lyrics files", func() {
+		var p string
+
+		BeforeEach(func() {
+			p = filepath.Join(GinkgoT().TempDir(), "song.lrc")
+		})
+
+		It("should rewrite files that are not normalized", func() {
+			data := mustEncode(japanese.ShiftJIS.NewEncoder().Bytes([]byte("[00:01.00]こんにちは\r\n")))
+			Expect(os.WriteFile(p, data, 0600)).To(Succeed())
+
+			enc, rewritten, err := music.NormalizeLyricsFile(p, music.LineEndingLF)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(enc).To(Equal(music.EncodingShiftJIS))
+			Expect(rewritten).To(BeTrue())
+
+			content, err := os.ReadFile(p)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("[00:01.00]こんにちは\n"))
+
+			info, err := os.Stat(p)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		})
+
+		It("should leave files of uncertain encoding untouched", func() {
+			data := mustEncode(charmap.Windows1251.NewEncoder().Bytes([]byte("[00:01.00]Привет\r\n")))
+			Expect(os.WriteFile(p, data, 0644)).To(Succeed())
+
+			_, rewritten, err := music.NormalizeLyricsFile(p, music.LineEndingLF)
+			Expect(err).To(MatchError(music.ErrUncertainEncoding))
+			Expect(rewritten).To(BeFalse())
+
+			content, err := os.ReadFile(p)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(content).To(Equal(data))
+		})
+
+		It("should leave normalized files untouched", func() {
+			Expect(os.WriteFile(p, []byte("[00:01.00]Hello\n"), 0644)).To(Succeed())
+
+			enc, rewritten, err := music.NormalizeLyricsFile(p, music.LineEndingLF)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(enc).To(Equal(music.EncodingUTF8))
+			Expect(rewritten).To(BeFalse())
+		})
+	})
+
+	It("should recognize lyrics files", func() {
+		Expect(music.IsLyricsFile("/music/song.lrc")).To(BeTrue())
+		Expect(music.IsLyricsFile("/music/song.TXT")).To(BeTrue())
+		Expect(music.IsLyricsFile("/music/song.flac")).To(BeFalse())
+	})
+})
@@ -0,0 +1,155 @@
+package music
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	FieldTitle       = "title"
+	FieldArtist      = "artist"
+	FieldAlbumArtist = "album_artist"
+	FieldAlbum       = "album"
+	FieldTrackNumber = "track"
+	FieldDiscNumber  = "disc"
+	FieldYear        = "year"
+)
+
+// placeholderPattern matches a placeholder of a path pattern such as {artist}.
+var placeholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// numericFields lists the fields only matching digits.
+var numericFields = map[string]bool{
+	FieldTrackNumber: true,
+	FieldDiscNumber:  true,
+	FieldYear:        true,
+}
+
+// PathPattern infers metadata from the path of an audio file, such as
+// "{artist}/{album}/{track} - {title}". The pattern is matched against the end of the path,
+// without the file extension. Supported placeholders are {artist}, {album_artist}, {album},
+// {title}, {track}, {disc}, {year} and {ignore}, which matches anything.
+type PathPattern struct {
+	pattern string
+	regexp  *regexp.Regexp
+}
+
+// NewPathPattern compiles a path pattern.
+func NewPathPattern(pattern string) (PathPattern, error) {
+	var b strings.Builder
+	b.WriteString(`(?:^|/)`)
+
+	seen := make(map[string]bool)
+	last := 0
+	for _, loc := range placeholderPattern.FindAllStringSubmatchIndex(pattern, -1) {
+		b.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		last = loc[1]
+
+		field := pattern[loc[2]:loc[3]]
+		switch {
+		case field == "ignore":
+			b.WriteString(`[^/]*?`)
+		case numericFields[field]:
+			b.WriteString(`(?P<` + field + `>\d+)`)
+		case field == FieldTitle || field == FieldArtist || field == FieldAlbumArtist || field == FieldAlbum:
+			b.WriteString(`(?P<` + field + `>[^/]+?)`)
+		default:
+			return PathPattern{}, fmt.Errorf("unknown placeholder {%s} in path pattern %q", field, pattern)
+		}
+
+		if field != "ignore" {
+			if seen[field] {
+				return PathPattern{}, fmt.Errorf("placeholder {%s} used twice in path pattern %q", field, pattern)
+			}
+			seen[field] = true
+		}
+	}
+	b.WriteString(regexp.QuoteMeta(pattern[last:]))
+	b.WriteString(`$`)
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return PathPattern{}, fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+	}
+
+	return PathPattern{pattern: pattern, regexp: re}, nil
+}
+
+// NewPathPatterns compiles several path patterns.
+func NewPathPatterns(patterns []string) ([]PathPattern, error) {
+	compiled := make([]PathPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		p, err := NewPathPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, p)
+	}
+	return compiled, nil
+}
+
+// String returns the pattern as written.
+func (p PathPattern) String() string {
+	return p.pattern
+}
+
+// Match returns the values of the fields found in the path of an audio file,
+// or false if the path doesn't match the pattern.
+func (p PathPattern) Match(path string) (map[string]string, bool) {
+	path = filepath.ToSlash(strings.TrimSuffix(path, filepath.Ext(path)))
+
+	match := p.regexp.FindStringSubmatch(path)
+	if match == nil {
+		return nil, false
+	}
+
+	fields := make(map[string]string)
+	for i, name := range p.regexp.SubexpNames() {
+		if name != "" {
+			fields[name] = strings.TrimSpace(match[i])
+		}
+	}
+	return fields, true
+}
+
+// inferFromPath fills the fields missing from the tags using the first pattern matching the path,
+// recording the inferred ones.
+func (m *Metadata) inferFromPath(path string, patterns []PathPattern) {
+	for _, pattern := range patterns {
+		fields, ok := pattern.Match(path)
+		if !ok {
+			continue
+		}
+
+		inferString := func(field string, value **string) {
+			if v := fields[field]; v != "" && (*value == nil || **value == "") {
+				*value = &v
+				m.Inferred = append(m.Inferred, field)
+			}
+		}
+		inferInt := func(field string, value *int) {
+			if n, err := strconv.Atoi(fields[field]); err == nil && n > 0 && *value == 0 {
+				*value = n
+				m.Inferred = append(m.Inferred, field)
+			}
+		}
+
+		inferString(FieldTitle, &m.Title)
+		inferString(FieldArtist, &m.Artist)
+		inferString(FieldAlbumArtist, &m.AlbumArtist)
+		inferString(FieldAlbum, &m.Album)
+		inferInt(FieldTrackNumber, &m.TrackNumber)
+		inferInt(FieldDiscNumber, &m.DiscNumber)
+		inferInt(FieldYear, &m.Year)
+
+		// The artist falls back to the album artist, like tags do
+		if m.Artist == nil && m.AlbumArtist != nil {
+			m.Artist = m.AlbumArtist
+			m.Inferred = append(m.Inferred, FieldArtist)
+		}
+		return
+	}
+}
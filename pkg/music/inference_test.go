@@ -0,0 +1,118 @@
+package music_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.senan.xyz/taglib"
+)
+
+var _ = Describe("PathPattern", func() {
+	When("compiling a pattern", func() {
+		It("should reject unknown placeholders", func() {
+			_, err := music.NewPathPattern("{artist}/{label}/{title}")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject placeholders used twice", func() {
+			_, err := music.NewPathPattern("{artist}/{artist} - {title}")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("matching a path", func() {
+		It("should extract the fields from the end of the path", func() {
+			pattern, err := music.NewPathPattern("{artist}/{album}/{track} - {title}")
+			Expect(err).ToNot(HaveOccurred())
+
+			fields, ok := pattern.Match("/music/Sleep Token/Take Me Back To Eden/05 - Vore.flac")
+			Expect(ok).To(BeTrue())
+			Expect(fields).To(Equal(map[string]string{
+				"artist": "Sleep Token",
+				"album":  "Take Me Back To Eden",
+				"track":  "05",
+				"title":  "Vore",
+			}))
+		})
+
+		It("should not match paths with another layout", func() {
+			pattern, err := music.NewPathPattern("{artist}/{album}/{track} - {title}")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, ok := pattern.Match("/music/Sleep Token/Vore.flac")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should skip ignored parts", func() {
+			pattern, err := music.NewPathPattern("{artist}/{ignore} {album}/{title}")
+			Expect(err).ToNot(HaveOccurred())
+
+			fields, ok := pattern.Match("Sleep Token/(2023) Take Me Back To Eden/Vore.mp3")
+			Expect(ok).To(BeTrue())
+			Expect(fields).To(HaveKeyWithValue("album", "Take Me Back To Eden"))
+		})
+	})
+
+	When("extracting metadata from an untagged file", func() {
+		var audioPath string
+		var library string
+
+		BeforeEach(func() {
+			library = GinkgoT().TempDir()
+			dir := filepath.Join(library, "Sleep Token", "Take Me Back To Eden")
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+
+			audioPath = filepath.Join(dir, "05 - Vore.flac")
+			Expect(os.Rename(copyFile("../test_data/Vore.flac", dir), audioPath)).To(Succeed())
+			Expect(taglib.WriteTags(audioPath, nil, taglib.Clear)).To(Succeed())
+		})
+
+		It("should leave missing fields empty without patterns", func() {
+			metadata, err := music.ExtractMetadata(audioPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metadata.Title).To(BeNil())
+			Expect(metadata.Artist).To(BeNil())
+			Expect(metadata.HasAllMetadata()).To(BeFalse())
+		})
+
+		It("should infer missing fields and mark them as inferred", func() {
+			patterns, err := music.NewPathPatterns([]string{"{artist}/{album}/{track} - {title}"})
+			Expect(err).ToNot(HaveOccurred())
+
+			metadata, err := music.ExtractMetadata(audioPath, music.WithPathPatterns([]string{library}, patterns...))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*metadata.Title).To(Equal("Vore"))
+			Expect(*metadata.Artist).To(Equal("Sleep Token"))
+			Expect(metadata.AlbumArtist).To(BeNil())
+			Expect(*metadata.Album).To(Equal("Take Me Back To Eden"))
+			Expect(metadata.TrackNumber).To(Equal(5))
+			Expect(metadata.IsInferred(music.FieldTitle)).To(BeTrue())
+			Expect(metadata.IsInferred(music.FieldAlbumArtist)).To(BeFalse())
+		})
+
+		It("should not infer from the library directories", func() {
+			patterns, err := music.NewPathPatterns([]string{"{artist}/{album}/{ignore}/{title}"})
+			Expect(err).ToNot(HaveOccurred())
+
+			metadata, err := music.ExtractMetadata(audioPath, music.WithPathPatterns([]string{library}, patterns...))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metadata.Title).To(BeNil())
+			Expect(metadata.Inferred).To(BeEmpty())
+		})
+	})
+
+	When("extracting metadata from a tagged file", func() {
+		It("should keep the tags", func() {
+			patterns, err := music.NewPathPatterns([]string{"{album}/{title}"})
+			Expect(err).ToNot(HaveOccurred())
+
+			metadata, err := music.ExtractMetadata("../test_data/Vore.flac", music.WithPathPatterns(nil, patterns...))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*metadata.Title).To(Equal("Vore"))
+			Expect(metadata.IsInferred(music.FieldTitle)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,39 @@
+package music
+
+import (
+	"strings"
+
+	"go.senan.xyz/taglib"
+)
+
+// INSTRUMENTAL_TAG is the tag marking a track as instrumental when set to a value such as "1" or "true".
+const INSTRUMENTAL_TAG = "INSTRUMENTAL"
+
+// NO_LINGUISTIC_CONTENT is the ISO 639-2 language code of tracks without lyrics.
+const NO_LINGUISTIC_CONTENT = "zxx"
+
+// InstrumentalSource tells what marked a track as instrumental.
+type InstrumentalSource string
+
+const (
+	// InstrumentalSourceProvider is used when the lyrics provider reports the track as instrumental.
+	InstrumentalSourceProvider InstrumentalSource = "provider"
+	// InstrumentalSourceTag is used when the tags of the track mark it as instrumental.
+	InstrumentalSourceTag InstrumentalSource = "tag"
+	// InstrumentalSourceManual is used when the user set the flag, which is then never overridden.
+	InstrumentalSourceManual InstrumentalSource = "manual"
+)
+
+// isInstrumental indicates whether the tags mark the audio as instrumental, either with a dedicated tag
+// or with a language telling it has no linguistic content.
+func isInstrumental(tags map[string][]string) bool {
+	if strings.EqualFold(firstTag(tags, taglib.Language), NO_LINGUISTIC_CONTENT) {
+		return true
+	}
+
+	switch strings.ToLower(firstTag(tags, INSTRUMENTAL_TAG)) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package lrc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OFFSET_TAG is the LRC tag adjusting every timestamp of the file, in milliseconds.
+// A positive offset makes the lyrics appear earlier.
+const OFFSET_TAG = "offset"
+
+var (
+	// timestampPattern matches a single LRC timestamp such as [01:23.45].
+	timestampPattern = regexp.MustCompile(`\[(\d+):(\d{2})(?:[.:](\d{1,3}))?\]`)
+	// leadingTimestampsPattern matches the timestamps at the start of a line.
+	leadingTimestampsPattern = regexp.MustCompile(`^(?:\s*\[\d+:\d{2}(?:[.:]\d{1,3})?\])+`)
+	// offsetTagPattern matches the [offset:] tag line.
+	offsetTagPattern = regexp.MustCompile(`(?mi)^\s*\[offset:\s*([+-]?\d+)\s*\]\s*$`)
+)
+
+// Line is a timestamped line of synced lyrics.
+type Line struct {
+	// Time is when the line starts
+	Time time.Duration
+	// Text is the content of the line
+	Text string
+}
+
+// Parse returns the timestamped lines of synced lyrics in the order they appear.
+// Lines carrying several timestamps are returned once per timestamp.
+// The [offset:] tag is applied to the returned times.
+func Parse(lyrics string) []Line {
+	offset := Offset(lyrics)
+
+	var lines []Line
+	for raw := range strings.Lines(lyrics) {
+		raw = strings.TrimRight(raw, "\r\n")
+		prefix := leadingTimestampsPattern.FindString(raw)
+		if prefix == "" {
+			continue
+		}
+
+		text := strings.TrimSpace(raw[len(prefix):])
+		for _, match := range timestampPattern.FindAllStringSubmatch(prefix, -1) {
+			t := parseTimestamp(match) - offset
+			if t < 0 {
+				t = 0
+			}
+			lines = append(lines, Line{Time: t, Text: text})
+		}
+	}
+	return lines
+}
+
+// StripTimestamps removes every LRC timestamp from lyrics, leaving their text.
+func StripTimestamps(lyrics string) string {
+	return timestampPattern.ReplaceAllString(lyrics, "")
+}
+
+// Offset returns the value of the [offset:] tag, zero if there is none.
+func Offset(lyrics string) time.Duration {
+	match := offsetTagPattern.FindStringSubmatch(lyrics)
+	if match == nil {
+		return 0
+	}
+
+	ms, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// Shift delays every timestamp of the lyrics by offset, or advances them when offset is negative.
+// Timestamps never go below zero. Everything else is kept as is.
+func Shift(lyrics string, offset time.Duration) string {
+	var b strings.Builder
+	for raw := range strings.Lines(lyrics) {
+		prefix := leadingTimestampsPattern.FindString(raw)
+		if prefix == "" {
+			b.WriteString(raw)
+			continue
+		}
+
+		b.WriteString(timestampPattern.ReplaceAllStringFunc(prefix, func(s string) string {
+			match := timestampPattern.FindStringSubmatch(s)
+			t := parseTimestamp(match) + offset
+			if t < 0 {
+				t = 0
+			}
+			return formatTimestamp(t, len(match[3]))
+		}))
+		b.WriteString(raw[len(prefix):])
+	}
+	return b.String()
+}
+
+// Delay delays the lyrics by offset through the [offset:] tag, adding to any existing offset.
+// A negative offset makes the lyrics appear earlier.
+func Delay(lyrics string, offset time.Duration) string {
+	total := Offset(lyrics) - offset
+	tag := fmt.Sprintf("[%s:%+d]", OFFSET_TAG, total.Milliseconds())
+
+	if offsetTagPattern.MatchString(lyrics) {
+		if total == 0 {
+			return offsetTagPattern.ReplaceAllString(lyrics, "")
+		}
+		return offsetTagPattern.ReplaceAllLiteralString(lyrics, tag)
+	}
+
+	if total == 0 {
+		return lyrics
+	}
+	return tag + "\n" + lyrics
+}
+
+// parseTimestamp converts a timestampPattern submatch to a duration.
+func parseTimestamp(match []string) time.Duration {
+	minutes, _ := strconv.Atoi(match[1])
+	seconds, _ := strconv.Atoi(match[2])
+
+	var fraction time.Duration
+	if match[3] != "" {
+		value, _ := strconv.Atoi(match[3])
+		switch len(match[3]) {
+		case 1:
+			fraction = time.Duration(value) * 100 * time.Millisecond
+		case 2:
+			fraction = time.Duration(value) * 10 * time.Millisecond
+		default:
+			fraction = time.Duration(value) * time.Millisecond
+		}
+	}
+
+	return time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second + fraction
+}
+
+// formatTimestamp formats a duration as an LRC timestamp, with millisecond precision when
+// digits is 3 and centisecond precision otherwise.
+func formatTimestamp(t time.Duration, digits int) string {
+	minutes := int(t / time.Minute)
+	seconds := int(t % time.Minute / time.Second)
+	ms := int(t % time.Second / time.Millisecond)
+
+	if digits == 3 {
+		return fmt.Sprintf("[%02d:%02d.%03d]", minutes, seconds, ms)
+	}
+	return fmt.Sprintf("[%02d:%02d.%02d]", minutes, seconds, ms/10)
+}
@@ -0,0 +1,13 @@
+package lrc_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestLrc(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Lrc Suite")
+}
@@ -0,0 +1,54 @@
+package lrc_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gerald-lbn/refrain/pkg/music/lrc"
+)
+
+var _ = Describe("Lrc", func() {
+	const lyrics = "[ar:Sleep Token]\n[00:15.27] You have become the voice in my head\n[00:21.76][01:02.5] Only recourse we're left after death\n\n[00:27.060] Your viscera welcome me in\n"
+
+	Context("Parse", func() {
+		It("should return every timestamped line", func() {
+			Expect(lrc.Parse(lyrics)).To(Equal([]lrc.Line{
+				{Time: 15*time.Second + 270*time.Millisecond, Text: "You have become the voice in my head"},
+				{Time: 21*time.Second + 760*time.Millisecond, Text: "Only recourse we're left after death"},
+				{Time: time.Minute + 2*time.Second + 500*time.Millisecond, Text: "Only recourse we're left after death"},
+				{Time: 27*time.Second + 60*time.Millisecond, Text: "Your viscera welcome me in"},
+			}))
+		})
+
+		It("should apply the offset tag", func() {
+			lines := lrc.Parse("[offset:+270]\n[00:15.27] Vore")
+			Expect(lines).To(Equal([]lrc.Line{{Time: 15 * time.Second, Text: "Vore"}}))
+		})
+	})
+
+	Context("Shift", func() {
+		It("should delay every timestamp and keep the rest untouched", func() {
+			Expect(lrc.Shift(lyrics, 250*time.Millisecond)).To(Equal(
+				"[ar:Sleep Token]\n[00:15.52] You have become the voice in my head\n[00:22.01][01:02.75] Only recourse we're left after death\n\n[00:27.310] Your viscera welcome me in\n",
+			))
+		})
+
+		It("should never go below zero", func() {
+			Expect(lrc.Shift("[00:00.50] Intro\r\n[00:02.00] Verse", -time.Second)).To(Equal("[00:00.00] Intro\r\n[00:01.00] Verse"))
+		})
+	})
+
+	Context("Delay", func() {
+		It("should add an offset tag", func() {
+			delayed := lrc.Delay("[00:15.27] Vore", 300*time.Millisecond)
+			Expect(delayed).To(Equal("[offset:-300]\n[00:15.27] Vore"))
+			Expect(lrc.Offset(delayed)).To(Equal(-300 * time.Millisecond))
+		})
+
+		It("should update an existing offset tag", func() {
+			Expect(lrc.Delay("[offset:+100]\n[00:15.27] Vore", -200*time.Millisecond)).To(Equal("[offset:+300]\n[00:15.27] Vore"))
+		})
+	})
+})
@@ -0,0 +1,70 @@
+package lrc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoTimestampedLines     = errors.New("synced lyrics have no timestamped line")
+	ErrNoTextLines            = errors.New("synced lyrics have no line with text")
+	ErrNonMonotonicTimestamps = errors.New("synced lyrics timestamps are not in ascending order")
+	ErrBeyondDuration         = errors.New("synced lyrics go beyond the end of the track")
+)
+
+// Validate checks that synced lyrics are consistent with a track lasting duration: timestamps must
+// be in ascending order, at least one line must have text, and the last timestamp must not go
+// beyond duration plus tolerance. A zero duration skips the last check.
+func Validate(lyrics string, duration, tolerance time.Duration) error {
+	lines := parseLinesInOrder(lyrics)
+	if len(lines) == 0 {
+		return ErrNoTimestampedLines
+	}
+
+	hasText := false
+	for i, line := range lines {
+		if line.Text != "" {
+			hasText = true
+		}
+		if i > 0 && line.Time < lines[i-1].Time {
+			return fmt.Errorf("%w: %s comes after %s", ErrNonMonotonicTimestamps,
+				formatTimestamp(line.Time, 2), formatTimestamp(lines[i-1].Time, 2))
+		}
+	}
+
+	if !hasText {
+		return ErrNoTextLines
+	}
+
+	last := lines[len(lines)-1].Time
+	if duration > 0 && last > duration+tolerance {
+		return fmt.Errorf("%w: last line at %s, track lasts %s", ErrBeyondDuration,
+			formatTimestamp(last, 2), formatTimestamp(duration, 2))
+	}
+
+	return nil
+}
+
+// parseLinesInOrder is like Parse, but lines carrying several timestamps are only returned
+// once for their first timestamp, so that repeated choruses don't break the ordering.
+func parseLinesInOrder(lyrics string) []Line {
+	offset := Offset(lyrics)
+
+	var lines []Line
+	for raw := range strings.Lines(lyrics) {
+		raw = strings.TrimRight(raw, "\r\n")
+		prefix := leadingTimestampsPattern.FindString(raw)
+		if prefix == "" {
+			continue
+		}
+
+		t := parseTimestamp(timestampPattern.FindStringSubmatch(prefix)) - offset
+		if t < 0 {
+			t = 0
+		}
+		lines = append(lines, Line{Time: t, Text: strings.TrimSpace(raw[len(prefix):])})
+	}
+	return lines
+}
@@ -0,0 +1,39 @@
+package lrc_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gerald-lbn/refrain/pkg/music/lrc"
+)
+
+var _ = Describe("Validate", func() {
+	const lyrics = "[00:15.27] You have become the voice in my head\n[00:21.76][01:02.50] Only recourse we're left after death\n[00:27.06]\n[00:33.52] Welcome me in\n"
+
+	It("should accept consistent lyrics", func() {
+		Expect(lrc.Validate(lyrics, 5*time.Minute, 5*time.Second)).To(Succeed())
+	})
+
+	It("should skip the duration check when the duration is unknown", func() {
+		Expect(lrc.Validate(lyrics, 0, 0)).To(Succeed())
+	})
+
+	It("should reject lyrics without timestamps", func() {
+		Expect(lrc.Validate("You have become the voice in my head", time.Minute, 0)).To(MatchError(lrc.ErrNoTimestampedLines))
+	})
+
+	It("should reject lyrics without text", func() {
+		Expect(lrc.Validate("[00:01.00]\n[00:02.00] ", time.Minute, 0)).To(MatchError(lrc.ErrNoTextLines))
+	})
+
+	It("should reject timestamps going backwards", func() {
+		Expect(lrc.Validate("[00:21.76] Second\n[00:15.27] First", time.Minute, 0)).To(MatchError(lrc.ErrNonMonotonicTimestamps))
+	})
+
+	It("should reject lyrics lasting longer than the track", func() {
+		Expect(lrc.Validate(lyrics, 30*time.Second, 2*time.Second)).To(MatchError(lrc.ErrBeyondDuration))
+		Expect(lrc.Validate(lyrics, 30*time.Second, 5*time.Second)).To(Succeed())
+	})
+})
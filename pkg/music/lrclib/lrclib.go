@@ -15,6 +15,7 @@ var (
 	ErrMissingTrackOrArtistName     = errors.New("track name and artist name are required")
 	ErrInvalidDuration              = errors.New("duration must be a positive integer")
 	ErrMissingID                    = errors.New("lyrics ID is required")
+	ErrLyricsNotFound               = errors.New("no lyrics found for the track")
 )
 
 const (
@@ -171,6 +172,9 @@ func (p *LRCLibProvider) GetLyrics(ctx context.Context, opts SearchLyricsOptions
 	}
 
 	// Check for invalid response
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrLyricsNotFound
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("LRCLib API request failed with status: %d. Reason: %v", resp.StatusCode, string(respBody))
 	}
@@ -218,6 +218,33 @@ var _ = Describe("LrcLib", func() {
 				Expect(err.Error()).To(Equal(fmt.Sprintf("LRCLib API request failed with status: %d. Reason: %s", status, body)))
 			})
 		})
+
+		When("the API does not know the track", func() {
+			It("should return ErrLyricsNotFound", func() {
+				body := "{\"message\":\"Failed to find specified track\",\"name\":\"TrackNotFound\",\"statusCode\":404}"
+				mockClient := &http.Client{
+					Transport: &mockRoundTripper{
+						roundTrip: func(req *http.Request) (*http.Response, error) {
+							return &http.Response{
+								StatusCode: http.StatusNotFound,
+								Body:       io.NopCloser(bytes.NewBufferString(body)),
+								Header:     make(http.Header),
+							}, nil
+						},
+					},
+				}
+				lrclibClient = lrclib.NewLRCLibProvider(lrclib.WithHttpClient(mockClient))
+
+				res, err := lrclibClient.GetLyrics(
+					ctx,
+					lrclib.WithTrackAndArtistName("Impose", "Bad Omens"),
+					263,
+				)
+
+				Expect(res).To(BeNil())
+				Expect(err).To(MatchError(lrclib.ErrLyricsNotFound))
+			})
+		})
 	})
 
 	Context("GetLyricsByID", func() {
@@ -0,0 +1,92 @@
+package music
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// LyricsProvider tells where the lyrics stored in the database come from.
+type LyricsProvider string
+
+const (
+	// LyricsProviderLRCLib is used for lyrics downloaded from LRCLib.
+	LyricsProviderLRCLib LyricsProvider = "lrclib"
+	// LyricsProviderSidecar is used for lyrics files found next to the audio file.
+	LyricsProviderSidecar LyricsProvider = "sidecar"
+	// LyricsProviderEmbedded is used for lyrics only found in the audio tags.
+	LyricsProviderEmbedded LyricsProvider = "embedded"
+)
+
+// LyricsAttemptOutcome tells how an attempt to fetch the lyrics of a track ended.
+type LyricsAttemptOutcome string
+
+const (
+	// LyricsAttemptHit is used when the provider returned lyrics that were kept.
+	LyricsAttemptHit LyricsAttemptOutcome = "hit"
+	// LyricsAttemptMiss is used when the provider has no lyrics for the query.
+	LyricsAttemptMiss LyricsAttemptOutcome = "miss"
+	// LyricsAttemptError is used when the provider could not be queried.
+	LyricsAttemptError LyricsAttemptOutcome = "error"
+	// LyricsAttemptRejected is used when the lyrics returned failed validation and were not kept.
+	LyricsAttemptRejected LyricsAttemptOutcome = "rejected"
+)
+
+// syncedLyricsLinePattern matches a line starting with an LRC timestamp such as [01:23.45].
+var syncedLyricsLinePattern = regexp.MustCompile(`^\[\d+:\d{2}(?:[.:]\d{1,3})?\]`)
+
+// IsSyncedLyrics reports whether the given lyrics contain at least one LRC timestamped line.
+func IsSyncedLyrics(lyrics string) bool {
+	for line := range strings.Lines(lyrics) {
+		if syncedLyricsLinePattern.MatchString(strings.TrimSpace(line)) {
+			return true
+		}
+	}
+	return false
+}
+
+// LyricsHash returns a hash of the plain and synced lyrics, used to notice when they change.
+// Lyrics are hashed once normalized to UTF-8 with LF line endings, so converting the encoding or the
+// line endings of a file is not mistaken for an edit.
+func LyricsHash(plain, synced string) string {
+	sum := sha256.Sum256([]byte(canonicalLyrics(plain) + "\x00" + canonicalLyrics(synced)))
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalLyrics returns lyrics as NormalizeText would write them with LF line endings, or as is
+// when their encoding is uncertain.
+func canonicalLyrics(lyrics string) string {
+	normalized, _, err := NormalizeText([]byte(lyrics), LineEndingLF)
+	if err != nil {
+		return lyrics
+	}
+	return string(normalized)
+}
+
+// ReadLyrics returns the plain and synced lyrics of the track, read from its lyrics files or, when
+// a file is missing, from its tags. It also tells whether the lyrics only come from the tags.
+func (m *Metadata) ReadLyrics() (plain, synced string, embedded bool, err error) {
+	plain, synced = m.EmbeddedPlainLyrics, m.EmbeddedSyncedLyrics
+	embedded = plain != "" || synced != ""
+
+	if m.HasPlainLyrics {
+		content, err := os.ReadFile(m.PlainLyricsPath)
+		if err != nil {
+			return "", "", false, err
+		}
+		plain = string(content)
+	}
+
+	if m.HasSyncedLyrics {
+		content, err := os.ReadFile(m.SyncedLyricsPath)
+		if err != nil {
+			return "", "", false, err
+		}
+		synced = string(content)
+	}
+
+	embedded = embedded && !m.HasPlainLyrics && !m.HasSyncedLyrics
+	return plain, synced, embedded, nil
+}
@@ -0,0 +1,71 @@
+package music
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Candidate describes lyrics found by a provider for a track.
+type Candidate struct {
+	TrackName  string
+	ArtistName string
+	AlbumName  string
+	// Duration is the length of the track the lyrics were written for, in seconds
+	Duration float64
+}
+
+// Mismatches lists the reasons why the candidate does not look like lyrics of the track.
+// Durations may differ by up to tolerance. Names are compared word by word, ignoring case,
+// diacritics, punctuation and extra words on either side. Albums are not compared since the same
+// recording often appears on several releases.
+func (m *Metadata) Mismatches(candidate Candidate, tolerance time.Duration) []string {
+	var mismatches []string
+
+	if m.Duration > 0 && candidate.Duration > 0 {
+		delta := time.Duration(math.Abs(m.Duration-candidate.Duration) * float64(time.Second))
+		if delta > tolerance {
+			mismatches = append(mismatches, fmt.Sprintf("duration differs by %s", delta.Round(time.Second)))
+		}
+	}
+
+	if m.Title != nil && !namesMatch(*m.Title, candidate.TrackName) {
+		mismatches = append(mismatches, fmt.Sprintf("title %q does not match %q", candidate.TrackName, *m.Title))
+	}
+
+	if m.Artist != nil && !namesMatch(*m.Artist, candidate.ArtistName) {
+		mismatches = append(mismatches, fmt.Sprintf("artist %q does not match %q", candidate.ArtistName, *m.Artist))
+	}
+
+	return mismatches
+}
+
+// namesMatch reports whether two names look alike once simplified, every word of one appearing in
+// the other. An empty name matches anything since there is nothing to compare.
+func namesMatch(a, b string) bool {
+	wordsA, wordsB := nameWords(a), nameWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return true
+	}
+	return containsWords(wordsA, wordsB) || containsWords(wordsB, wordsA)
+}
+
+// nameWords splits a name into lowercase words of letters and digits, without diacritics.
+func nameWords(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(RemoveDiacritics(name)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsWords reports whether every word of others is one of words.
+func containsWords(words, others []string) bool {
+	for _, word := range others {
+		if !slices.Contains(words, word) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package music_test
+
+import (
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mismatches", func() {
+	var metadata *music.Metadata
+
+	BeforeEach(func() {
+		title, artist, album := "Vore", "Sleep Token", "Take Me Back To Eden"
+		metadata = &music.Metadata{Title: &title, Artist: &artist, Album: &album, Duration: 339.5}
+	})
+
+	It("should accept a matching candidate", func() {
+		Expect(metadata.Mismatches(music.Candidate{
+			TrackName:  "vore",
+			ArtistName: "Sleep Token",
+			AlbumName:  "Vore",
+			Duration:   341,
+		}, 3*time.Second)).To(BeEmpty())
+	})
+
+	It("should accept names with extra words", func() {
+		Expect(metadata.Mismatches(music.Candidate{
+			TrackName:  "Vore (Live)",
+			ArtistName: "Sleep Token",
+			Duration:   339,
+		}, 3*time.Second)).To(BeEmpty())
+	})
+
+	It("should report a different duration", func() {
+		Expect(metadata.Mismatches(music.Candidate{
+			TrackName:  "Vore",
+			ArtistName: "Sleep Token",
+			Duration:   263,
+		}, 3*time.Second)).To(ConsistOf(ContainSubstring("duration")))
+	})
+
+	It("should report different names", func() {
+		Expect(metadata.Mismatches(music.Candidate{
+			TrackName:  "Impose",
+			ArtistName: "Bad Omens",
+			Duration:   339,
+		}, 3*time.Second)).To(ConsistOf(ContainSubstring("title"), ContainSubstring("artist")))
+	})
+
+	It("should not match names only sharing letters", func() {
+		artist := "Air"
+		metadata.Artist = &artist
+		Expect(metadata.Mismatches(music.Candidate{
+			TrackName:  "Vore",
+			ArtistName: "Fair Warning",
+			Duration:   339,
+		}, 3*time.Second)).To(ConsistOf(ContainSubstring("artist")))
+	})
+
+	It("should ignore case, diacritics and punctuation", func() {
+		Expect(metadata.Mismatches(music.Candidate{
+			TrackName:  "VORÉ!",
+			ArtistName: "sleep-token",
+			Duration:   339,
+		}, 3*time.Second)).To(BeEmpty())
+	})
+})
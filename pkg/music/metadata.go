@@ -2,9 +2,13 @@ package music
 
 import (
 	"errors"
+	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/gerald-lbn/refrain/pkg/music/cue"
 	"github.com/gerald-lbn/refrain/pkg/utils/file"
 	"go.senan.xyz/taglib"
 )
@@ -14,22 +18,82 @@ const (
 	PLAIN_LYRICS_EXTENSION  = "txt"
 )
 
+// embeddedLyricsTags lists the tag keys that may hold lyrics embedded in an audio file.
+// taglib exposes Vorbis comments as is and maps the ID3v2 USLT frame to LYRICS.
+// ID3v2 SYLT frames are not part of taglib's property interface, so synced lyrics are
+// only detected when a tagger stored LRC content in one of these keys.
+var embeddedLyricsTags = []string{taglib.Lyrics, "UNSYNCEDLYRICS", SYNCED_LYRICS_TAG}
+
+// ARTISTS_TAG is the multi-valued artist tag written by taggers such as MusicBrainz Picard.
+const ARTISTS_TAG = "ARTISTS"
+
+// ARTISTS_SEPARATOR separates the artists stored in a single tag value, as in "Artist A; Artist B".
+const ARTISTS_SEPARATOR = ";"
+
 var (
 	ErrNoExtensionInPath = errors.New("No extension found in path")
 )
 
 // Metadata contains the metadata properties of an audio file
 type Metadata struct {
-	// Path is the absolute filepath the audio
+	// Path is the absolute filepath the audio, or its virtual track path for tracks of a CUE sheet
 	Path string
+	// AudioPath is the file holding the audio, which differs from Path for tracks of a CUE sheet
+	AudioPath string
+	// CueSheetPath points to the CUE sheet describing the audio, if it is a track of a single-file album
+	CueSheetPath string
+	// Start is the position of the audio in AudioPath in seconds, zero unless it comes from a CUE sheet
+	Start float64
 	// Title is the name of the audio
 	Title *string
-	// Artist is the album artist's name of the audio
+	// Artist is the name of the artist performing the audio, falling back to the album artist
 	Artist *string
+	// Artists lists every artist performing the audio, the primary one first
+	Artists []string
+	// AlbumArtist is the name of the album artist, nil when untagged, see PrimaryAlbumArtist
+	AlbumArtist *string
 	// Album is the name of the album the audio belongs to
 	Album *string
 	// Duration is the length of the audio in seconds
 	Duration float64
+	// TrackNumber is the position of the audio on its disc, zero when unknown
+	TrackNumber int
+	// DiscNumber is the disc of the album the audio is on, zero when unknown
+	DiscNumber int
+	// ReleaseDate is the release date of the audio, as tagged
+	ReleaseDate string
+	// Year is the release year of the audio, zero when unknown
+	Year int
+	// Genre is the genre of the audio
+	Genre string
+	// Composer is the composer of the audio
+	Composer string
+	// MusicBrainzRecordingID is the MusicBrainz ID of the recording
+	MusicBrainzRecordingID string
+	// MusicBrainzReleaseID is the MusicBrainz ID of the release the audio belongs to
+	MusicBrainzReleaseID string
+	// MusicBrainzArtistID is the MusicBrainz ID of the primary artist of the audio
+	MusicBrainzArtistID string
+	// MusicBrainzAlbumArtistID is the MusicBrainz ID of the artist the release is credited to
+	MusicBrainzAlbumArtistID string
+	// ISRC is the International Standard Recording Code of the audio
+	ISRC string
+	// Format is the codec of the audio, such as aac or alac for MP4 files, see ReadCodec
+	Format string
+	// Bitrate is the bitrate of the audio in kbit/s
+	Bitrate int
+	// SampleRate is the sample rate of the audio in Hz
+	SampleRate int
+	// Channels is the number of audio channels
+	Channels int
+	// FileSize is the size of the audio file in bytes
+	FileSize int64
+	// HasEmbeddedCover indicates whether the audio tags carry a picture
+	HasEmbeddedCover bool
+	// CoverPath points to a cover art file such as cover.jpg in the directory of the audio, if any
+	CoverPath string
+	// Instrumental indicates whether the tags mark the audio as instrumental
+	Instrumental bool
 	// HasPlainLyrics indicates whether the audio has plain lyrics stored locally
 	HasPlainLyrics bool
 	// PlainLyricsPath points to the plain lyrics stored locally
@@ -38,68 +102,307 @@ type Metadata struct {
 	HasSyncedLyrics bool
 	// SyncedLyricsPath points to the synced lyrics stored locally
 	SyncedLyricsPath string
+	// EmbeddedPlainLyrics holds the plain lyrics found in the audio tags, if any
+	EmbeddedPlainLyrics string
+	// EmbeddedSyncedLyrics holds the synced lyrics found in the audio tags, if any
+	EmbeddedSyncedLyrics string
+	// Inferred lists the fields inferred from the path of the audio rather than read from its tags
+	Inferred []string
 }
 
 func (m *Metadata) HasAllMetadata() bool {
 	return m.Title != nil && m.Artist != nil && m.Album != nil
 }
 
+// PrimaryArtist returns the first of the artists performing the audio.
+func (m *Metadata) PrimaryArtist() string {
+	if len(m.Artists) > 0 {
+		return m.Artists[0]
+	}
+	return valueOf(m.Artist)
+}
+
+// PrimaryAlbumArtist returns the artist the album is credited to, falling back to the primary artist
+// of the audio, along with its MusicBrainz ID when tagged.
+func (m *Metadata) PrimaryAlbumArtist() (string, string) {
+	name := m.PrimaryArtist()
+	if m.AlbumArtist != nil && *m.AlbumArtist != "" {
+		name = *m.AlbumArtist
+	}
+
+	musicBrainzID := m.MusicBrainzAlbumArtistID
+	if musicBrainzID == "" && strings.EqualFold(name, m.PrimaryArtist()) {
+		musicBrainzID = m.MusicBrainzArtistID
+	}
+	return name, musicBrainzID
+}
+
 func (m *Metadata) HasBothLyricsStoredLocally() bool {
 	return m.HasPlainLyrics && m.HasSyncedLyrics
 }
 
+// IsInferred indicates whether the given field was inferred from the path of the audio.
+func (m *Metadata) IsInferred(field string) bool {
+	return slices.Contains(m.Inferred, field)
+}
+
+// HasEmbeddedLyrics indicates whether the audio file carries lyrics in its tags.
+func (m *Metadata) HasEmbeddedLyrics() bool {
+	return m.EmbeddedPlainLyrics != "" || m.EmbeddedSyncedLyrics != ""
+}
+
+// ExtractOption configures how metadata are extracted.
+type ExtractOption func(*extractOptions)
+
+type extractOptions struct {
+	lyricsPathTemplate *LyricsPathTemplate
+	pathPatterns       []PathPattern
+	patternLibraries   []string
+}
+
+// WithLyricsPathTemplate looks for lyrics at the paths built from the given template
+// instead of next to the audio file.
+func WithLyricsPathTemplate(t LyricsPathTemplate) ExtractOption {
+	return func(o *extractOptions) {
+		o.lyricsPathTemplate = &t
+	}
+}
+
+// WithPathPatterns infers the fields missing from the tags from the path of the audio file,
+// using the first matching pattern. Patterns are matched against the path relative to the
+// library containing the audio file, if any, so library directories are never mistaken for tags.
+func WithPathPatterns(libraries []string, patterns ...PathPattern) ExtractOption {
+	return func(o *extractOptions) {
+		o.patternLibraries = libraries
+		o.pathPatterns = append(o.pathPatterns, patterns...)
+	}
+}
+
 // ExtractMetadata extracts metadata from an audio file specified by its path.
-func ExtractMetadata(p string) (*Metadata, error) {
-	tags, err := taglib.ReadTags(p)
+func ExtractMetadata(p string, opts ...ExtractOption) (*Metadata, error) {
+	options := &extractOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	// Tracks of a CUE sheet are read from the audio file holding them
+	audioPath := p
+	var sheetPath string
+	var sheet *cue.Sheet
+	var cueTrack cue.Track
+	if audio, number, ok := ParseVirtualTrackPath(p); ok {
+		var err error
+		audioPath = audio
+		sheetPath, sheet, err = FindCueSheet(audio)
+		if err != nil {
+			return nil, err
+		}
+		if sheet == nil {
+			return nil, ErrNotInCueSheet
+		}
+
+		if cueTrack, ok = sheet.Track(number); !ok {
+			return nil, ErrNotInCueSheet
+		}
+	}
+
+	info, err := os.Stat(audioPath)
 	if err != nil {
 		return nil, err
 	}
 
-	properties, err := taglib.ReadProperties(p)
+	tags, err := taglib.ReadTags(audioPath)
 	if err != nil {
 		return nil, err
 	}
 
-	var title string
-	if len(tags[taglib.Title]) > 0 {
-		title = tags[taglib.Title][0]
+	properties, err := taglib.ReadProperties(audioPath)
+	if err != nil {
+		return nil, err
 	}
 
-	var artist string
-	if len(tags[taglib.AlbumArtist]) > 0 {
-		artist = tags[taglib.AlbumArtist][0]
+	// An unreadable container still has the codec its extension tells
+	format, _ := ReadCodec(audioPath)
+
+	date := firstTag(tags, taglib.Date, "YEAR", taglib.OriginalDate)
+
+	embeddedPlainLyrics, embeddedSyncedLyrics := readEmbeddedLyrics(tags)
+
+	metadata := &Metadata{
+		Path:        p,
+		AudioPath:   audioPath,
+		Title:       optionalTag(tags, taglib.Title),
+		Album:       optionalTag(tags, taglib.Album),
+		Artist:      optionalTag(tags, taglib.Artist, taglib.AlbumArtist),
+		AlbumArtist: optionalTag(tags, taglib.AlbumArtist),
+		Artists:     readArtists(tags),
+		Duration:    properties.Length.Seconds(),
+		Format:      format,
+		Bitrate:     int(properties.Bitrate),
+		SampleRate:  int(properties.SampleRate),
+		Channels:    int(properties.Channels),
+		FileSize:    info.Size(),
+
+		HasEmbeddedCover: len(properties.Images) > 0,
+		CoverPath:        findCoverFile(filepath.Dir(audioPath)),
+
+		TrackNumber:              parseNumber(firstTag(tags, taglib.TrackNumber)),
+		DiscNumber:               parseNumber(firstTag(tags, taglib.DiscNumber)),
+		ReleaseDate:              date,
+		Year:                     parseYear(date),
+		Genre:                    firstTag(tags, taglib.Genre),
+		Composer:                 firstTag(tags, taglib.Composer),
+		MusicBrainzRecordingID:   firstTag(tags, taglib.MusicBrainzTrackID),
+		MusicBrainzReleaseID:     firstTag(tags, taglib.MusicBrainzAlbumID),
+		MusicBrainzArtistID:      firstTag(tags, taglib.MusicBrainzArtistID),
+		MusicBrainzAlbumArtistID: firstTag(tags, taglib.MusicBrainzAlbumArtistID),
+		ISRC:                     firstTag(tags, taglib.ISRC),
+
+		Instrumental:         isInstrumental(tags),
+		EmbeddedPlainLyrics:  embeddedPlainLyrics,
+		EmbeddedSyncedLyrics: embeddedSyncedLyrics,
 	}
 
-	var album string
-	if len(tags[taglib.Album]) > 0 {
-		album = tags[taglib.Album][0]
+	if sheet != nil {
+		metadata.applyCueTrack(sheetPath, sheet, cueTrack, properties.Length)
 	}
 
-	plainLyricsPath, err := GeneratePlainLyricsFilePathFromAudioFilePath(p)
-	if err != nil {
-		return nil, err
+	// The path of a track of a CUE sheet is the one of the whole album
+	if len(options.pathPatterns) > 0 && !metadata.IsVirtual() {
+		rel := p
+		if _, dir, err := locateInLibraries(options.patternLibraries, filepath.Dir(p)); err == nil {
+			rel = filepath.Join(dir, filepath.Base(p))
+		}
+		metadata.inferFromPath(rel, options.pathPatterns)
 	}
 
-	hasPlainLyrics := file.Exists(plainLyricsPath)
+	if len(metadata.Artists) == 0 && metadata.Artist != nil {
+		metadata.Artists = splitArtists([]string{*metadata.Artist})
+	}
+
+	if options.lyricsPathTemplate != nil || metadata.IsVirtual() {
+		template := LyricsPathTemplate{}
+		if options.lyricsPathTemplate != nil {
+			template = *options.lyricsPathTemplate
+		}
+
+		metadata.PlainLyricsPath, err = template.Resolve(audioPath, metadata, PLAIN_LYRICS_EXTENSION)
+		if err != nil {
+			return nil, err
+		}
+
+		metadata.SyncedLyricsPath, err = template.Resolve(audioPath, metadata, SYNCED_LYRICS_EXTENSION)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		metadata.PlainLyricsPath, err = GeneratePlainLyricsFilePathFromAudioFilePath(p)
+		if err != nil {
+			return nil, err
+		}
+
+		metadata.SyncedLyricsPath, err = GenerateSyncedLyricsFilePathFromAudioFilePath(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	metadata.HasPlainLyrics = file.Exists(metadata.PlainLyricsPath)
+	metadata.HasSyncedLyrics = file.Exists(metadata.SyncedLyricsPath)
+
+	return metadata, nil
+}
+
+// firstTag returns the first non-empty value found for the given keys, in order.
+func firstTag(tags map[string][]string, keys ...string) string {
+	for _, key := range keys {
+		for _, value := range tags[key] {
+			if value = strings.TrimSpace(value); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
 
-	syncedLyricsPath, err := GenerateSyncedLyricsFilePathFromAudioFilePath(p)
+// readArtists returns every artist found in the tags, preferring the multi-valued ARTISTS tag.
+func readArtists(tags map[string][]string) []string {
+	for _, key := range []string{ARTISTS_TAG, taglib.Artist, taglib.AlbumArtist} {
+		if artists := splitArtists(tags[key]); len(artists) > 0 {
+			return artists
+		}
+	}
+	return nil
+}
+
+// splitArtists splits tag values holding several artists and removes duplicates, keeping their order.
+func splitArtists(values []string) []string {
+	var artists []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, artist := range strings.Split(value, ARTISTS_SEPARATOR) {
+			artist = strings.TrimSpace(artist)
+			if artist == "" || seen[strings.ToLower(artist)] {
+				continue
+			}
+			seen[strings.ToLower(artist)] = true
+			artists = append(artists, artist)
+		}
+	}
+	return artists
+}
+
+// optionalTag is like firstTag but returns nil when none of the keys has a value.
+func optionalTag(tags map[string][]string, keys ...string) *string {
+	value := firstTag(tags, keys...)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// parseNumber parses track and disc numbers, which may be tagged as "5" or "5/12".
+// It returns zero for values which are not numbers.
+func parseNumber(value string) int {
+	number, _, _ := strings.Cut(value, "/")
+	n, err := strconv.Atoi(strings.TrimSpace(number))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// parseYear extracts the year of a date tagged as "2023", "2023-03-19" or similar.
+func parseYear(date string) int {
+	if len(date) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(date[:4])
 	if err != nil {
-		return nil, err
+		return 0
 	}
+	return year
+}
 
-	hasSyncedLyrics := file.Exists(syncedLyricsPath)
+// readEmbeddedLyrics looks for lyrics in the audio tags and sorts them into plain and synced
+// lyrics based on their content, since the tag key alone does not tell them apart.
+func readEmbeddedLyrics(tags map[string][]string) (plain, synced string) {
+	for _, key := range embeddedLyricsTags {
+		for _, value := range tags[key] {
+			if strings.TrimSpace(value) == "" {
+				continue
+			}
 
-	return &Metadata{
-		Path:             p,
-		Title:            &title,
-		Album:            &album,
-		Artist:           &artist,
-		Duration:         properties.Length.Seconds(),
-		HasPlainLyrics:   hasPlainLyrics,
-		PlainLyricsPath:  plainLyricsPath,
-		HasSyncedLyrics:  hasSyncedLyrics,
-		SyncedLyricsPath: syncedLyricsPath,
-	}, nil
+			if IsSyncedLyrics(value) {
+				if synced == "" {
+					synced = value
+				}
+			} else if plain == "" {
+				plain = value
+			}
+		}
+	}
+	return plain, synced
 }
 
 // generateLyricsFilePathFromAudioFilePath is a helper function to create a lyrics file path
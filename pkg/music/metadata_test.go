@@ -1,11 +1,25 @@
 package music_test
 
 import (
+	"os"
+	"path/filepath"
+
 	"github.com/gerald-lbn/refrain/pkg/music"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"go.senan.xyz/taglib"
 )
 
+// copyFile copies src into dir and returns the path of the copy.
+func copyFile(src, dir string) string {
+	data, err := os.ReadFile(src)
+	Expect(err).ToNot(HaveOccurred())
+
+	dst := filepath.Join(dir, filepath.Base(src))
+	Expect(os.WriteFile(dst, data, 0644)).To(Succeed())
+	return dst
+}
+
 var _ = Describe("Music", func() {
 	var voreAudioPath = "../test_data/Vore.flac"
 	var vorePlainLyrics = "../test_data/Vore.txt"
@@ -25,12 +39,30 @@ var _ = Describe("Music", func() {
 				Expect(*metadata.Artist).To(Equal("Sleep Token"))
 				Expect(*metadata.Album).To(Equal("Take Me Back To Eden"))
 				Expect(metadata.Duration).To(BeNumerically("~", 4, 1))
+				Expect(metadata.TrackNumber).To(Equal(5))
+				Expect(metadata.DiscNumber).To(Equal(1))
+				Expect(metadata.ReleaseDate).To(Equal("2023"))
+				Expect(metadata.Year).To(Equal(2023))
+				Expect(metadata.ISRC).To(Equal("GBUM72200353"))
+				Expect(metadata.Genre).To(BeEmpty())
+				Expect(metadata.MusicBrainzRecordingID).To(BeEmpty())
 				Expect(metadata.HasPlainLyrics).To(BeTrue())
 				Expect(metadata.HasSyncedLyrics).To(BeTrue())
 				Expect(metadata.PlainLyricsPath).To(Equal(vorePlainLyrics))
 				Expect(metadata.SyncedLyricsPath).To(Equal(voreSyncedLyrics))
 			})
 
+			It("should return the audio properties", func() {
+				info, err := os.Stat(voreAudioPath)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(metadata.Format).To(Equal("flac"))
+				Expect(metadata.SampleRate).To(BeNumerically(">", 0))
+				Expect(metadata.Channels).To(BeNumerically(">", 0))
+				Expect(metadata.Bitrate).To(BeNumerically(">", 0))
+				Expect(metadata.FileSize).To(Equal(info.Size()))
+			})
+
 			It("should return true if all metadata are set", func() {
 				Expect(metadata.HasAllMetadata()).To(BeTrue())
 			})
@@ -38,6 +70,149 @@ var _ = Describe("Music", func() {
 			It("should return true if both lyrics are stored locally", func() {
 				Expect(metadata.HasBothLyricsStoredLocally()).To(BeTrue())
 			})
+
+			It("should not report embedded lyrics", func() {
+				Expect(metadata.HasEmbeddedLyrics()).To(BeFalse())
+			})
+
+			It("should read the lyrics files", func() {
+				plain, synced, embedded, err := metadata.ReadLyrics()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(plain).ToNot(BeEmpty())
+				Expect(music.IsSyncedLyrics(synced)).To(BeTrue())
+				Expect(embedded).To(BeFalse())
+			})
+		})
+
+		Context("from an audio file with embedded lyrics", func() {
+			var audioPath string
+
+			BeforeEach(func() {
+				audioPath = copyFile(voreAudioPath, GinkgoT().TempDir())
+			})
+
+			It("should detect plain lyrics stored in the LYRICS tag", func() {
+				Expect(taglib.WriteTags(audioPath, map[string][]string{
+					taglib.Lyrics: {"You have become the voice in my head"},
+				}, 0)).To(Succeed())
+
+				metadata, err := music.ExtractMetadata(audioPath)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metadata.HasEmbeddedLyrics()).To(BeTrue())
+				Expect(metadata.EmbeddedPlainLyrics).To(Equal("You have become the voice in my head"))
+				Expect(metadata.EmbeddedSyncedLyrics).To(BeEmpty())
+				Expect(metadata.HasPlainLyrics).To(BeFalse())
+
+				plain, synced, embedded, err := metadata.ReadLyrics()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(plain).To(Equal("You have become the voice in my head"))
+				Expect(synced).To(BeEmpty())
+				Expect(embedded).To(BeTrue())
+			})
+
+			It("should read numbers tagged with a total and MusicBrainz IDs", func() {
+				Expect(taglib.WriteTags(audioPath, map[string][]string{
+					taglib.TrackNumber:        {"5/10"},
+					taglib.DiscNumber:         {"2/2"},
+					taglib.Date:               {"2023-05-19"},
+					taglib.Genre:              {"Progressive Metal"},
+					taglib.Composer:           {"Vessel"},
+					taglib.MusicBrainzTrackID: {"0b3b6d8a-2a8c-4d8f-9d8a-2f0c7e1b5f6a"},
+					taglib.MusicBrainzAlbumID: {"6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"},
+				}, 0)).To(Succeed())
+
+				metadata, err := music.ExtractMetadata(audioPath)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metadata.TrackNumber).To(Equal(5))
+				Expect(metadata.DiscNumber).To(Equal(2))
+				Expect(metadata.ReleaseDate).To(Equal("2023-05-19"))
+				Expect(metadata.Year).To(Equal(2023))
+				Expect(metadata.Genre).To(Equal("Progressive Metal"))
+				Expect(metadata.Composer).To(Equal("Vessel"))
+				Expect(metadata.MusicBrainzRecordingID).To(Equal("0b3b6d8a-2a8c-4d8f-9d8a-2f0c7e1b5f6a"))
+				Expect(metadata.MusicBrainzReleaseID).To(Equal("6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"))
+			})
+
+			It("should credit the album to the album artist, or else to the primary artist", func() {
+				Expect(taglib.WriteTags(audioPath, map[string][]string{
+					taglib.MusicBrainzArtistID: {"a1b2c3d4-0000-4000-8000-000000000001"},
+				}, 0)).To(Succeed())
+
+				metadata, err := music.ExtractMetadata(audioPath)
+				Expect(err).ToNot(HaveOccurred())
+				name, musicBrainzID := metadata.PrimaryAlbumArtist()
+				Expect(name).To(Equal("Sleep Token"))
+				Expect(musicBrainzID).To(Equal("a1b2c3d4-0000-4000-8000-000000000001"))
+
+				Expect(taglib.WriteTags(audioPath, map[string][]string{
+					taglib.AlbumArtist:              {"Various Artists"},
+					taglib.MusicBrainzAlbumArtistID: {"89ad4ac3-39f7-470e-963a-56509c546377"},
+				}, 0)).To(Succeed())
+
+				metadata, err = music.ExtractMetadata(audioPath)
+				Expect(err).ToNot(HaveOccurred())
+				name, musicBrainzID = metadata.PrimaryAlbumArtist()
+				Expect(name).To(Equal("Various Artists"))
+				Expect(musicBrainzID).To(Equal("89ad4ac3-39f7-470e-963a-56509c546377"))
+				Expect(metadata.MusicBrainzArtistID).To(Equal("a1b2c3d4-0000-4000-8000-000000000001"))
+			})
+
+			It("should read every artist of the track", func() {
+				Expect(taglib.WriteTags(audioPath, map[string][]string{
+					taglib.Artist: {"Artist A; Artist B", "Artist C", "artist a"},
+				}, 0)).To(Succeed())
+
+				metadata, err := music.ExtractMetadata(audioPath)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(*metadata.Artist).To(Equal("Artist A; Artist B"))
+				Expect(metadata.Artists).To(Equal([]string{"Artist A", "Artist B", "Artist C"}))
+				Expect(metadata.PrimaryArtist()).To(Equal("Artist A"))
+			})
+
+			It("should prefer the multi-valued artists tag", func() {
+				Expect(taglib.WriteTags(audioPath, map[string][]string{
+					taglib.Artist:     {"Artist A feat. Artist B"},
+					music.ARTISTS_TAG: {"Artist A", "Artist B"},
+				}, 0)).To(Succeed())
+
+				metadata, err := music.ExtractMetadata(audioPath)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metadata.Artists).To(Equal([]string{"Artist A", "Artist B"}))
+			})
+
+			It("should detect instrumental tracks from their tags", func() {
+				metadata, err := music.ExtractMetadata(audioPath)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metadata.Instrumental).To(BeFalse())
+
+				Expect(taglib.WriteTags(audioPath, map[string][]string{
+					taglib.Language: {"zxx"},
+				}, 0)).To(Succeed())
+
+				metadata, err = music.ExtractMetadata(audioPath)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metadata.Instrumental).To(BeTrue())
+
+				Expect(taglib.WriteTags(audioPath, map[string][]string{
+					taglib.Language:        {"eng"},
+					music.INSTRUMENTAL_TAG: {"True"},
+				}, 0)).To(Succeed())
+
+				metadata, err = music.ExtractMetadata(audioPath)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metadata.Instrumental).To(BeTrue())
+			})
+
+			It("should detect synced lyrics stored in an unsynced lyrics tag", func() {
+				Expect(taglib.WriteTags(audioPath, map[string][]string{
+					"UNSYNCEDLYRICS": {"[00:15.27] You have become the voice in my head"},
+				}, 0)).To(Succeed())
+
+				metadata, err := music.ExtractMetadata(audioPath)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metadata.EmbeddedPlainLyrics).To(BeEmpty())
+				Expect(metadata.EmbeddedSyncedLyrics).To(Equal("[00:15.27] You have become the voice in my head"))
+			})
 		})
 
 		Context("from a non audio file", func() {
@@ -51,6 +226,33 @@ var _ = Describe("Music", func() {
 		})
 	})
 
+	When("detecting synced lyrics", func() {
+		It("should recognize LRC timestamps", func() {
+			Expect(music.IsSyncedLyrics("[ar:Sleep Token]\n[00:15.27] You have become the voice in my head")).To(BeTrue())
+			Expect(music.IsSyncedLyrics("[1:05] Welcome me in")).To(BeTrue())
+		})
+
+		It("should not flag plain lyrics", func() {
+			Expect(music.IsSyncedLyrics("You have become the voice in my head\nWelcome me in")).To(BeFalse())
+			Expect(music.IsSyncedLyrics("[Chorus]\nWelcome me in")).To(BeFalse())
+		})
+	})
+
+	When("hashing lyrics", func() {
+		It("should change when any lyrics change", func() {
+			hash := music.LyricsHash("Welcome me in", "[00:01.00] Welcome me in")
+			Expect(hash).To(Equal(music.LyricsHash("Welcome me in", "[00:01.00] Welcome me in")))
+			Expect(hash).ToNot(Equal(music.LyricsHash("Welcome me in", "[00:02.00] Welcome me in")))
+			Expect(music.LyricsHash("ab", "")).ToNot(Equal(music.LyricsHash("a", "b")))
+		})
+
+		It("should not change when the lyrics are normalized", func() {
+			hash := music.LyricsHash("Café\nMüller\n", "[00:01.00] Café\n")
+			Expect(music.LyricsHash("\uFEFFCafé\r\nMüller\r\n", "[00:01.00] Café\r")).To(Equal(hash))
+			Expect(music.LyricsHash("Caf\xe9\nM\xfcller\n", "[00:01.00] Caf\xe9\n")).To(Equal(hash))
+		})
+	})
+
 	When("generating path", func() {
 		Context("for synced lyrics", func() {
 			It("should generate a synced lyrics file path with .lrc extension", func() {
@@ -0,0 +1,205 @@
+package music
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DEFAULT_NORMALIZATION_KEYWORDS lists the words marking the parts of a title or an album name
+// describing a release rather than the song, such as "(2011 Remaster)" or "- Live at Wembley".
+var DEFAULT_NORMALIZATION_KEYWORDS = []string{
+	"remaster", "remastered", "live", "version", "edit", "mix", "mono", "stereo",
+	"deluxe", "edition", "bonus", "demo", "explicit", "clean", "single", "anniversary",
+}
+
+// DEFAULT_MAX_NORMALIZED_QUERIES is the number of normalized queries tried after the raw tags when
+// the rules do not tell.
+const DEFAULT_MAX_NORMALIZED_QUERIES = 4
+
+var (
+	// bracketsPattern matches a bracketed part of a name, without nested brackets.
+	bracketsPattern = regexp.MustCompile(`\s*[(\[{]([^()\[\]{}]*)[)\]}]`)
+	// suffixPattern matches a part of a name following a dash, such as " - Live at Wembley".
+	suffixPattern = regexp.MustCompile(`\s+[-–—]\s+(.+)$`)
+	// bracketedFeaturingPattern matches featured artists between brackets, such as "(feat. X)".
+	bracketedFeaturingPattern = regexp.MustCompile(`(?i)\s*[(\[]\s*(?:feat\.?|ft\.?|featuring)\s+([^)\]]+)[)\]]`)
+	// trailingFeaturingPattern matches featured artists at the end of a name, such as "A feat. B".
+	trailingFeaturingPattern = regexp.MustCompile(`(?i)\s+(?:feat\.?|ft\.?|featuring)\s+(.+)$`)
+	// artistSeparatorPattern splits a list of featured artists.
+	artistSeparatorPattern = regexp.MustCompile(`\s*(?:,|;|&|\band\b)\s*`)
+	// andPattern matches the word "and" between two names.
+	andPattern = regexp.MustCompile(`(?i) and `)
+	// spacesPattern matches consecutive whitespaces.
+	spacesPattern = regexp.MustCompile(`\s+`)
+)
+
+// punctuationReplacer replaces typographic punctuation by its ASCII counterpart.
+var punctuationReplacer = strings.NewReplacer(
+	"‘", "'", "’", "'", "“", `"`, "”", `"`, "–", "-", "—", "-", "…", "...",
+)
+
+// NormalizationRules configures how tags are simplified to find lyrics when the raw tags are not found.
+type NormalizationRules struct {
+	// FoldUnicode removes diacritics and replaces typographic punctuation
+	FoldUnicode bool
+	// StripBrackets removes the bracketed parts of titles and album names containing a keyword
+	StripBrackets bool
+	// StripSuffixes removes the parts of titles and album names following a dash and containing a keyword
+	StripSuffixes bool
+	// ExtractFeaturing removes featured artists from titles and artists
+	ExtractFeaturing bool
+	// AmpersandVariants also tries artists with "&" replaced by "and", and the other way around
+	AmpersandVariants bool
+	// Keywords lists the words marking the parts to strip, DEFAULT_NORMALIZATION_KEYWORDS when empty
+	Keywords []string
+	// MaxQueries caps the normalized queries tried after the raw tags, so a track only sends a few
+	// requests to the provider, DEFAULT_MAX_NORMALIZED_QUERIES when zero
+	MaxQueries int
+}
+
+// DefaultNormalizationRules returns rules enabling every normalization.
+func DefaultNormalizationRules() NormalizationRules {
+	return NormalizationRules{
+		FoldUnicode:       true,
+		StripBrackets:     true,
+		StripSuffixes:     true,
+		ExtractFeaturing:  true,
+		AmpersandVariants: true,
+	}
+}
+
+// Normalizer simplifies tags according to NormalizationRules.
+type Normalizer struct {
+	rules    NormalizationRules
+	keywords *regexp.Regexp
+}
+
+// NewNormalizer creates a Normalizer applying the given rules.
+func NewNormalizer(rules NormalizationRules) *Normalizer {
+	keywords := rules.Keywords
+	if len(keywords) == 0 {
+		keywords = DEFAULT_NORMALIZATION_KEYWORDS
+	}
+
+	quoted := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		quoted = append(quoted, regexp.QuoteMeta(keyword))
+	}
+
+	return &Normalizer{
+		rules:    rules,
+		keywords: regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`),
+	}
+}
+
+// maxQueries returns the number of normalized queries to try.
+func (n *Normalizer) maxQueries() int {
+	if n.rules.MaxQueries <= 0 {
+		return DEFAULT_MAX_NORMALIZED_QUERIES
+	}
+	return n.rules.MaxQueries
+}
+
+// Title normalizes a track title, returning the featured artists it mentions.
+func (n *Normalizer) Title(title string) (string, []string) {
+	title, featured := n.extractFeaturing(n.fold(title))
+	return n.stripRelease(title), featured
+}
+
+// Artist normalizes an artist name, returning the featured artists it mentions.
+func (n *Normalizer) Artist(artist string) (string, []string) {
+	return n.extractFeaturing(n.fold(artist))
+}
+
+// Album normalizes an album name.
+func (n *Normalizer) Album(album string) string {
+	return n.stripRelease(n.fold(album))
+}
+
+// ArtistVariants returns the alternate spellings of an artist name, such as "A and B" for "A & B".
+func (n *Normalizer) ArtistVariants(artist string) []string {
+	if !n.rules.AmpersandVariants {
+		return nil
+	}
+
+	var variants []string
+	if strings.Contains(artist, " & ") {
+		variants = append(variants, strings.ReplaceAll(artist, " & ", " and "))
+	}
+	if andPattern.MatchString(artist) {
+		variants = append(variants, andPattern.ReplaceAllString(artist, " & "))
+	}
+	return variants
+}
+
+// fold removes diacritics and typographic punctuation when enabled.
+func (n *Normalizer) fold(s string) string {
+	if n.rules.FoldUnicode {
+		s = punctuationReplacer.Replace(RemoveDiacritics(s))
+	}
+	return clean(s)
+}
+
+// RemoveDiacritics folds the accented letters of s into their base letters, such as "é" into "e".
+func RemoveDiacritics(s string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+	if err != nil {
+		return s
+	}
+	return folded
+}
+
+// extractFeaturing removes the featured artists from a name when enabled.
+func (n *Normalizer) extractFeaturing(s string) (string, []string) {
+	if !n.rules.ExtractFeaturing {
+		return s, nil
+	}
+
+	var featured []string
+	for _, pattern := range []*regexp.Regexp{bracketedFeaturingPattern, trailingFeaturingPattern} {
+		for _, match := range pattern.FindAllStringSubmatch(s, -1) {
+			for _, name := range artistSeparatorPattern.Split(match[1], -1) {
+				if name = strings.TrimSpace(name); name != "" {
+					featured = append(featured, name)
+				}
+			}
+		}
+		s = pattern.ReplaceAllString(s, "")
+	}
+	return clean(s), featured
+}
+
+// stripRelease removes the bracketed parts and the suffixes describing the release when enabled.
+func (n *Normalizer) stripRelease(name string) string {
+	s := name
+	if n.rules.StripBrackets {
+		s = bracketsPattern.ReplaceAllStringFunc(s, func(part string) string {
+			if n.keywords.MatchString(part) {
+				return ""
+			}
+			return part
+		})
+	}
+
+	if n.rules.StripSuffixes {
+		if match := suffixPattern.FindStringSubmatchIndex(s); match != nil && n.keywords.MatchString(s[match[2]:match[3]]) {
+			s = s[:match[0]]
+		}
+	}
+
+	// Never strip a name down to nothing
+	if s = clean(s); s == "" {
+		return name
+	}
+	return s
+}
+
+// clean trims a name and collapses its whitespaces.
+func clean(s string) string {
+	return strings.TrimSpace(spacesPattern.ReplaceAllString(s, " "))
+}
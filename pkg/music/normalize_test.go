@@ -0,0 +1,81 @@
+package music_test
+
+import (
+	"github.com/gerald-lbn/refrain/pkg/music"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Normalizer", func() {
+	var normalizer *music.Normalizer
+
+	BeforeEach(func() {
+		normalizer = music.NewNormalizer(music.DefaultNormalizationRules())
+	})
+
+	When("normalizing titles", func() {
+		DescribeTable("should strip the parts describing the release",
+			func(title, expected string) {
+				normalized, _ := normalizer.Title(title)
+				Expect(normalized).To(Equal(expected))
+			},
+			Entry("remaster between brackets", "Song (2011 Remaster)", "Song"),
+			Entry("live suffix", "Song - Live at Wembley", "Song"),
+			Entry("remastered suffix", "Song - Remastered 2009", "Song"),
+			Entry("unrelated brackets", "Song (Interlude)", "Song (Interlude)"),
+			Entry("unrelated suffix", "Part 1 - The Beginning", "Part 1 - The Beginning"),
+			Entry("diacritics", "Café Müller", "Cafe Muller"),
+			Entry("typographic apostrophe", "Don’t Stop", "Don't Stop"),
+			Entry("name made of a keyword", "(Live)", "(Live)"),
+			Entry("word starting with a keyword", "Song (Mixtape)", "Song (Mixtape)"),
+			Entry("keyword starting a word", "Song - Singles Club", "Song - Singles Club"),
+		)
+
+		It("should extract featured artists", func() {
+			title, featured := normalizer.Title("Song [feat. X & Y]")
+			Expect(title).To(Equal("Song"))
+			Expect(featured).To(Equal([]string{"X", "Y"}))
+		})
+	})
+
+	When("normalizing artists", func() {
+		It("should extract featured artists", func() {
+			artist, featured := normalizer.Artist("A ft. B, C")
+			Expect(artist).To(Equal("A"))
+			Expect(featured).To(Equal([]string{"B", "C"}))
+		})
+
+		It("should not mistake words for featuring", func() {
+			artist, featured := normalizer.Artist("Daft Punk")
+			Expect(artist).To(Equal("Daft Punk"))
+			Expect(featured).To(BeEmpty())
+		})
+
+		It("should spell ampersands both ways", func() {
+			Expect(normalizer.ArtistVariants("A & B")).To(Equal([]string{"A and B"}))
+			Expect(normalizer.ArtistVariants("A and B")).To(Equal([]string{"A & B"}))
+			Expect(normalizer.ArtistVariants("AB")).To(BeEmpty())
+		})
+	})
+
+	When("rules are disabled", func() {
+		It("should only clean whitespaces", func() {
+			normalizer := music.NewNormalizer(music.NormalizationRules{})
+			title, featured := normalizer.Title("  Café  (feat. X) (2011 Remaster) ")
+			Expect(title).To(Equal("Café (feat. X) (2011 Remaster)"))
+			Expect(featured).To(BeEmpty())
+			Expect(normalizer.ArtistVariants("A & B")).To(BeEmpty())
+		})
+	})
+
+	When("using custom keywords", func() {
+		It("should only strip parts containing them", func() {
+			rules := music.DefaultNormalizationRules()
+			rules.Keywords = []string{"taylor's version"}
+			normalizer := music.NewNormalizer(rules)
+
+			title, _ := normalizer.Title("Love Story (Taylor's Version) (Live)")
+			Expect(title).To(Equal("Love Story (Live)"))
+		})
+	})
+})
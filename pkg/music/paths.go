@@ -0,0 +1,326 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gerald-lbn/refrain/pkg/utils/file"
+)
+
+// DEFAULT_LYRICS_PATH_TEMPLATE stores lyrics next to the audio file, sharing its base name.
+const DEFAULT_LYRICS_PATH_TEMPLATE = "{dir}/{basename}.{ext}"
+
+var (
+	ErrMissingExtensionPlaceholder = errors.New("lyrics path template must end with {ext}, .lrc or .txt")
+	ErrMissingLyricsRoot           = errors.New("lyrics path template uses {root} but no lyrics root is configured")
+	ErrNotInLibrary                = errors.New("audio file is not located in any library")
+)
+
+// LyricsPathTemplate builds lyrics file paths from a pattern.
+//
+// Supported placeholders are:
+//   - {library}: the library containing the audio file
+//   - {root}: the configured lyrics root
+//   - {relative_dir}: the directory of the audio file, relative to its library
+//   - {dir}: the directory of the audio file
+//   - {basename}: the name of the audio file without its extension
+//   - {artist}, {album_artist}, {album}, {title}: the audio tags
+//   - {track}: the track number, padded to two digits
+//   - {ext}: the lyrics file extension
+//
+// A template ending with a literal .lrc or .txt extension, such as
+// "{library}/.lyrics/{artist}/{album}/{title}.lrc", is used for both lyrics files, the extension
+// being replaced by the one of each file.
+//
+// Tracks of a CUE sheet share their audio file, so they use CueTemplate, which should tell them
+// apart with {title} or {track}.
+type LyricsPathTemplate struct {
+	// Template is the pattern of the lyrics file path
+	Template string
+	// CueTemplate is the pattern of the lyrics file path of the tracks of a CUE sheet
+	CueTemplate string
+	// Root is a writable directory where lyrics can be stored apart from the audio files
+	Root string
+	// Libraries lists the library paths the audio files belong to
+	Libraries []string
+}
+
+// Resolve returns the path of the lyrics file with the extension ext for the audio file at p.
+func (t LyricsPathTemplate) Resolve(p string, m *Metadata, ext string) (string, error) {
+	template := t.Template
+	if template == "" {
+		template = DEFAULT_LYRICS_PATH_TEMPLATE
+	}
+	if m.IsVirtual() {
+		template = t.CueTemplate
+		if template == "" {
+			template = DEFAULT_CUE_LYRICS_PATH_TEMPLATE
+		}
+	}
+
+	template, err := withExtensionPlaceholder(template)
+	if err != nil {
+		return "", err
+	}
+
+	audioExt := filepath.Ext(p)
+	if audioExt == "" {
+		return "", ErrNoExtensionInPath
+	}
+
+	dir, name := filepath.Split(p)
+	basename := strings.TrimSuffix(name, audioExt)
+
+	// Tracks without album artist are credited to their artist
+	albumArtist := m.AlbumArtist
+	if albumArtist == nil {
+		albumArtist = m.Artist
+	}
+
+	replacements := []string{
+		"{dir}", filepath.Clean(dir),
+		"{basename}", basename,
+		"{artist}", pathComponent(m.Artist, "Unknown Artist"),
+		"{album_artist}", pathComponent(albumArtist, "Unknown Artist"),
+		"{album}", pathComponent(m.Album, "Unknown Album"),
+		"{title}", pathComponent(m.Title, basename),
+		"{track}", fmt.Sprintf("%02d", m.TrackNumber),
+		"{ext}", ext,
+	}
+
+	if strings.Contains(template, "{root}") {
+		if t.Root == "" {
+			return "", ErrMissingLyricsRoot
+		}
+		replacements = append(replacements, "{root}", filepath.Clean(t.Root))
+	}
+
+	if strings.Contains(template, "{library}") || strings.Contains(template, "{relative_dir}") {
+		library, relativeDir, err := t.locate(dir)
+		if err != nil {
+			return "", err
+		}
+		replacements = append(replacements, "{library}", library, "{relative_dir}", relativeDir)
+	}
+
+	return filepath.Clean(strings.NewReplacer(replacements...).Replace(template)), nil
+}
+
+// Matches reports whether p is a lyrics file the template could have resolved, which tells lyrics
+// apart from other text files lying among the audio files, such as notes. When p tells the
+// directory of the audio file, that directory must hold an audio file, named after {basename} when
+// the template uses it.
+func (t LyricsPathTemplate) Matches(p string) bool {
+	if !IsLyricsFile(p) {
+		return false
+	}
+
+	template, cueTemplate := t.Template, t.CueTemplate
+	if template == "" {
+		template = DEFAULT_LYRICS_PATH_TEMPLATE
+	}
+	if cueTemplate == "" {
+		cueTemplate = DEFAULT_CUE_LYRICS_PATH_TEMPLATE
+	}
+
+	p = filepath.Clean(p)
+	for _, template := range []string{template, cueTemplate} {
+		pattern, err := t.pattern(template)
+		if err != nil {
+			continue
+		}
+
+		match := pattern.FindStringSubmatch(p)
+		if match == nil {
+			continue
+		}
+
+		group := func(name string) string {
+			if i := pattern.SubexpIndex(name); i >= 0 {
+				return match[i]
+			}
+			return ""
+		}
+
+		var dirs []string
+		switch {
+		case strings.Contains(template, "{dir}"):
+			dirs = []string{group("dir")}
+		case strings.Contains(template, "{library}") && strings.Contains(template, "{relative_dir}"):
+			dirs = []string{filepath.Join(group("library"), group("relative_dir"))}
+		case strings.Contains(template, "{relative_dir}"):
+			for _, library := range t.Libraries {
+				dirs = append(dirs, filepath.Join(library, group("relative_dir")))
+			}
+		default:
+			// The lyrics are named after tags, so the audio file cannot be told from p
+			return true
+		}
+
+		for _, dir := range dirs {
+			if hasAudioFile(dir, group("basename")) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// pattern returns a regular expression matching the paths the template resolves to.
+func (t LyricsPathTemplate) pattern(template string) (*regexp.Regexp, error) {
+	template, err := withExtensionPlaceholder(template)
+	if err != nil {
+		return nil, err
+	}
+
+	libraries := make([]string, 0, len(t.Libraries))
+	for _, library := range t.Libraries {
+		libraries = append(libraries, regexp.QuoteMeta(filepath.Clean(library)))
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	last := 0
+	for _, loc := range placeholderPattern.FindAllStringIndex(template, -1) {
+		literal := template[last:loc[0]]
+		last = loc[1]
+
+		switch placeholder := template[loc[0]:loc[1]]; placeholder {
+		case "{relative_dir}":
+			// The relative directory of audio files at the root of the library is cleaned away
+			if strings.HasSuffix(literal, "/") {
+				expr.WriteString(regexp.QuoteMeta(strings.TrimSuffix(literal, "/")))
+				expr.WriteString(`(?:/(?P<relative_dir>.+))?`)
+				continue
+			}
+			expr.WriteString(regexp.QuoteMeta(literal))
+			expr.WriteString(`(?P<relative_dir>.+)`)
+		case "{library}":
+			if len(libraries) == 0 {
+				return nil, ErrNotInLibrary
+			}
+			expr.WriteString(regexp.QuoteMeta(literal))
+			expr.WriteString(`(?P<library>` + strings.Join(libraries, "|") + `)`)
+		case "{root}":
+			if t.Root == "" {
+				return nil, ErrMissingLyricsRoot
+			}
+			expr.WriteString(regexp.QuoteMeta(literal))
+			expr.WriteString(regexp.QuoteMeta(filepath.Clean(t.Root)))
+		case "{dir}":
+			expr.WriteString(regexp.QuoteMeta(literal))
+			expr.WriteString(`(?P<dir>.+)`)
+		case "{basename}":
+			expr.WriteString(regexp.QuoteMeta(literal))
+			expr.WriteString(`(?P<basename>[^/]+)`)
+		case "{artist}", "{album_artist}", "{album}", "{title}":
+			expr.WriteString(regexp.QuoteMeta(literal))
+			expr.WriteString(`[^/]+`)
+		case "{track}":
+			expr.WriteString(regexp.QuoteMeta(literal))
+			expr.WriteString(`\d+`)
+		case "{ext}":
+			expr.WriteString(regexp.QuoteMeta(literal))
+			expr.WriteString(`(?i:` + SYNCED_LYRICS_EXTENSION + `|` + PLAIN_LYRICS_EXTENSION + `)`)
+		default:
+			expr.WriteString(regexp.QuoteMeta(literal + placeholder))
+		}
+	}
+	expr.WriteString(regexp.QuoteMeta(template[last:]))
+	expr.WriteString("$")
+
+	return regexp.Compile(expr.String())
+}
+
+// hasAudioFile reports whether dir holds an audio file, named stem when it is not empty.
+func hasAudioFile(dir, stem string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || IsLyricsFile(name) || IsCueSheet(name) {
+			continue
+		}
+		if stem != "" && strings.TrimSuffix(name, filepath.Ext(name)) != stem {
+			continue
+		}
+
+		if isAudio, err := file.IsAudioFile(filepath.Join(dir, name)); err == nil && isAudio {
+			return true
+		}
+	}
+	return false
+}
+
+// withExtensionPlaceholder returns the template with its literal lyrics extension, if any, replaced
+// by the {ext} placeholder.
+func withExtensionPlaceholder(template string) (string, error) {
+	if strings.Contains(template, "{ext}") {
+		return template, nil
+	}
+
+	ext := strings.TrimPrefix(filepath.Ext(template), ".")
+	if !strings.EqualFold(ext, SYNCED_LYRICS_EXTENSION) && !strings.EqualFold(ext, PLAIN_LYRICS_EXTENSION) {
+		return "", ErrMissingExtensionPlaceholder
+	}
+	return strings.TrimSuffix(template, ext) + "{ext}", nil
+}
+
+// locate returns the library containing dir and the path of dir relative to it.
+func (t LyricsPathTemplate) locate(dir string) (string, string, error) {
+	return locateInLibraries(t.Libraries, dir)
+}
+
+// locateInLibraries returns the first of the libraries containing dir and the path of dir relative to it.
+func locateInLibraries(libraries []string, dir string) (string, string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, library := range libraries {
+		absLibrary, err := filepath.Abs(library)
+		if err != nil {
+			continue
+		}
+
+		rel, err := filepath.Rel(absLibrary, absDir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		return filepath.Clean(library), rel, nil
+	}
+
+	return "", "", ErrNotInLibrary
+}
+
+// pathComponent turns a tag value into a safe path component, using fallback when the tag is empty.
+func pathComponent(value *string, fallback string) string {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return fallback
+	}
+
+	component := strings.Map(func(r rune) rune {
+		switch {
+		case r == '/' || r == '\\':
+			return '-'
+		case r < 0x20:
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(*value))
+
+	if component == "." || component == ".." {
+		return fallback
+	}
+	return component
+}
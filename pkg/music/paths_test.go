@@ -0,0 +1,159 @@
+package music_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LyricsPathTemplate", func() {
+	var metadata *music.Metadata
+
+	BeforeEach(func() {
+		title, artist, album := "Vore", "Sleep Token", "Take Me Back To Eden"
+		metadata = &music.Metadata{Title: &title, Artist: &artist, Album: &album}
+	})
+
+	When("resolving a lyrics path", func() {
+		It("should store lyrics next to the audio file by default", func() {
+			path, err := music.LyricsPathTemplate{}.Resolve("/music/Sleep Token/05 Vore.flac", metadata, "lrc")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).To(Equal("/music/Sleep Token/05 Vore.lrc"))
+		})
+
+		It("should expand tags and the library", func() {
+			template := music.LyricsPathTemplate{
+				Template:  "{library}/.lyrics/{artist}/{album}/{title}.{ext}",
+				Libraries: []string{"/other", "/music"},
+			}
+
+			path, err := template.Resolve("/music/Sleep Token/05 Vore.flac", metadata, "txt")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).To(Equal("/music/.lyrics/Sleep Token/Take Me Back To Eden/Vore.txt"))
+		})
+
+		It("should expand the lyrics root and the directory relative to the library", func() {
+			template := music.LyricsPathTemplate{
+				Template:  "{root}/{relative_dir}/{basename}.{ext}",
+				Root:      "/data/lyrics",
+				Libraries: []string{"/music"},
+			}
+
+			path, err := template.Resolve("/music/Sleep Token/Take Me Back To Eden/05 Vore.flac", metadata, "lrc")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).To(Equal("/data/lyrics/Sleep Token/Take Me Back To Eden/05 Vore.lrc"))
+		})
+
+		It("should sanitize tags and fall back when they are missing", func() {
+			artist, empty := "AC/DC", ""
+			metadata.Artist = &artist
+			metadata.Title = &empty
+
+			path, err := music.LyricsPathTemplate{Template: "{root}/{artist}/{album}/{title}.{ext}", Root: "/lyrics"}.
+				Resolve("/music/Back In Black.mp3", metadata, "lrc")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).To(Equal("/lyrics/AC-DC/Take Me Back To Eden/Back In Black.lrc"))
+		})
+
+		It("should replace a literal lyrics extension by the one of each file", func() {
+			template := music.LyricsPathTemplate{Template: "{library}/.lyrics/{artist}/{album}/{title}.lrc", Libraries: []string{"/music"}}
+
+			synced, err := template.Resolve("/music/a.flac", metadata, "lrc")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(synced).To(HaveSuffix(".lrc"))
+
+			plain, err := template.Resolve("/music/a.flac", metadata, "txt")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plain).To(Equal(strings.TrimSuffix(synced, ".lrc") + ".txt"))
+		})
+
+		It("should reject templates without an extension", func() {
+			_, err := music.LyricsPathTemplate{Template: "{dir}/{basename}"}.Resolve("/music/a.flac", metadata, "lrc")
+			Expect(err).To(MatchError(music.ErrMissingExtensionPlaceholder))
+
+			_, err = music.LyricsPathTemplate{Template: "{dir}/{basename}.lyrics"}.Resolve("/music/a.flac", metadata, "lrc")
+			Expect(err).To(MatchError(music.ErrMissingExtensionPlaceholder))
+		})
+
+		It("should require a lyrics root when the template uses it", func() {
+			_, err := music.LyricsPathTemplate{Template: "{root}/{basename}.{ext}"}.Resolve("/music/a.flac", metadata, "lrc")
+			Expect(err).To(MatchError(music.ErrMissingLyricsRoot))
+		})
+
+		It("should fail when the audio file is outside the libraries", func() {
+			template := music.LyricsPathTemplate{Template: "{library}/{basename}.{ext}", Libraries: []string{"/music"}}
+			_, err := template.Resolve("/musicals/a.flac", metadata, "lrc")
+			Expect(err).To(MatchError(music.ErrNotInLibrary))
+		})
+	})
+
+	When("matching a lyrics path", func() {
+		var library string
+
+		BeforeEach(func() {
+			library = GinkgoT().TempDir()
+			copyFile("../test_data/Vore.flac", library)
+			Expect(os.WriteFile(filepath.Join(library, "info.txt"), []byte("Ripped in 2024"), 0644)).To(Succeed())
+		})
+
+		It("should match lyrics named after an audio file", func() {
+			template := music.LyricsPathTemplate{}
+			Expect(template.Matches(filepath.Join(library, "Vore.lrc"))).To(BeTrue())
+			Expect(template.Matches(filepath.Join(library, "Vore.txt"))).To(BeTrue())
+		})
+
+		It("should not match other text files", func() {
+			template := music.LyricsPathTemplate{}
+			Expect(template.Matches(filepath.Join(library, "info.txt"))).To(BeFalse())
+			Expect(template.Matches(filepath.Join(library, "Vore.flac"))).To(BeFalse())
+		})
+
+		It("should match lyrics of CUE tracks next to their audio file", func() {
+			Expect(music.LyricsPathTemplate{}.Matches(filepath.Join(library, "Sleep Token - Vore.lrc"))).To(BeTrue())
+		})
+
+		It("should match lyrics stored apart from the audio files", func() {
+			template := music.LyricsPathTemplate{
+				Template:  "{library}/.lyrics/{artist}/{album}/{title}.lrc",
+				Libraries: []string{library},
+			}
+			Expect(template.Matches(filepath.Join(library, ".lyrics", "Sleep Token", "Take Me Back To Eden", "Vore.txt"))).To(BeTrue())
+			Expect(template.Matches(filepath.Join(library, ".lyrics", "Vore.txt"))).To(BeFalse())
+		})
+
+		It("should match lyrics in the lyrics root mirroring the library", func() {
+			root := GinkgoT().TempDir()
+			template := music.LyricsPathTemplate{
+				Template:  "{root}/{relative_dir}/{basename}.{ext}",
+				Root:      root,
+				Libraries: []string{library},
+			}
+			Expect(template.Matches(filepath.Join(root, "Vore.lrc"))).To(BeTrue())
+			Expect(template.Matches(filepath.Join(root, "info.txt"))).To(BeFalse())
+		})
+	})
+
+	When("extracting metadata with a template", func() {
+		It("should look for lyrics at the templated paths", func() {
+			library := GinkgoT().TempDir()
+			audioPath := copyFile("../test_data/Vore.flac", library)
+			lyricsDir := filepath.Join(library, ".lyrics", "Sleep Token")
+			Expect(os.MkdirAll(lyricsDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(lyricsDir, "Vore.lrc"), []byte("[00:15.27] Vore"), 0644)).To(Succeed())
+
+			metadata, err := music.ExtractMetadata(audioPath, music.WithLyricsPathTemplate(music.LyricsPathTemplate{
+				Template:  "{library}/.lyrics/{artist}/{title}.{ext}",
+				Libraries: []string{library},
+			}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metadata.SyncedLyricsPath).To(Equal(filepath.Join(lyricsDir, "Vore.lrc")))
+			Expect(metadata.HasSyncedLyrics).To(BeTrue())
+			Expect(metadata.PlainLyricsPath).To(Equal(filepath.Join(lyricsDir, "Vore.txt")))
+			Expect(metadata.HasPlainLyrics).To(BeFalse())
+		})
+	})
+})
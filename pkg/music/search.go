@@ -0,0 +1,84 @@
+package music
+
+import "strings"
+
+// SearchQuery holds the names used to look lyrics up for a track.
+type SearchQuery struct {
+	TrackName  string
+	ArtistName string
+	AlbumName  string
+}
+
+// SearchQueries returns the queries to try, in order, to find lyrics for the track.
+// The track artist is tried first, then the album artist when it differs. Tracks performed by
+// several artists are then tried with their primary artist and with all their artists joined.
+// When a normalizer is given, the normalized variants of the tags follow the raw tags,
+// first with the album then without it, up to the number of queries its rules allow.
+// Queries missing the track or artist name are left out.
+func (m *Metadata) SearchQueries(n *Normalizer) []SearchQuery {
+	var queries []SearchQuery
+	seen := make(map[SearchQuery]bool)
+
+	add := func(query SearchQuery) {
+		if query.TrackName == "" || query.ArtistName == "" {
+			return
+		}
+
+		key := SearchQuery{
+			TrackName:  strings.ToLower(query.TrackName),
+			ArtistName: strings.ToLower(query.ArtistName),
+			AlbumName:  strings.ToLower(query.AlbumName),
+		}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		queries = append(queries, query)
+	}
+
+	title, album := valueOf(m.Title), valueOf(m.Album)
+	artists := []string{valueOf(m.Artist), valueOf(m.AlbumArtist)}
+	if len(m.Artists) > 1 {
+		artists = append(artists,
+			m.PrimaryArtist(),
+			strings.Join(m.Artists, ", "),
+			strings.Join(m.Artists, " & "),
+		)
+	}
+	for _, artist := range artists {
+		add(SearchQuery{TrackName: title, ArtistName: artist, AlbumName: album})
+	}
+
+	if n == nil {
+		return queries
+	}
+
+	normalizedTitle, _ := n.Title(title)
+	normalizedAlbum := n.Album(album)
+	var variants []SearchQuery
+	for _, artist := range artists {
+		normalizedArtist, _ := n.Artist(artist)
+		for _, name := range append([]string{normalizedArtist}, n.ArtistVariants(normalizedArtist)...) {
+			variants = append(variants, SearchQuery{TrackName: normalizedTitle, ArtistName: name, AlbumName: normalizedAlbum})
+		}
+	}
+
+	raw := len(queries)
+	for _, variant := range variants {
+		add(variant)
+	}
+	for _, variant := range variants {
+		variant.AlbumName = ""
+		add(variant)
+	}
+
+	return queries[:min(len(queries), raw+n.maxQueries())]
+}
+
+// valueOf returns the trimmed string pointed to by s, or an empty string if s is nil.
+func valueOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
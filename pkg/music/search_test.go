@@ -0,0 +1,94 @@
+package music_test
+
+import (
+	"github.com/gerald-lbn/refrain/pkg/music"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SearchQueries", func() {
+	ptr := func(s string) *string { return &s }
+
+	It("should prefer the track artist and fall back to the album artist", func() {
+		metadata := &music.Metadata{
+			Title:       ptr("Sleep"),
+			Artist:      ptr("Sleep Token"),
+			AlbumArtist: ptr("Various Artists"),
+			Album:       ptr("Metal Hits"),
+		}
+
+		Expect(metadata.SearchQueries(nil)).To(Equal([]music.SearchQuery{
+			{TrackName: "Sleep", ArtistName: "Sleep Token", AlbumName: "Metal Hits"},
+			{TrackName: "Sleep", ArtistName: "Various Artists", AlbumName: "Metal Hits"},
+		}))
+	})
+
+	It("should not repeat a query when both artists are the same", func() {
+		metadata := &music.Metadata{
+			Title:       ptr("Vore"),
+			Artist:      ptr("Sleep Token"),
+			AlbumArtist: ptr("sleep token"),
+			Album:       ptr(""),
+		}
+
+		Expect(metadata.SearchQueries(nil)).To(Equal([]music.SearchQuery{
+			{TrackName: "Vore", ArtistName: "Sleep Token"},
+		}))
+	})
+
+	It("should try the primary artist and the joined artists", func() {
+		metadata := &music.Metadata{
+			Title:       ptr("Song"),
+			Artist:      ptr("A; B"),
+			AlbumArtist: ptr("A; B"),
+			Artists:     []string{"A", "B"},
+		}
+
+		Expect(metadata.SearchQueries(nil)).To(Equal([]music.SearchQuery{
+			{TrackName: "Song", ArtistName: "A; B"},
+			{TrackName: "Song", ArtistName: "A"},
+			{TrackName: "Song", ArtistName: "A, B"},
+			{TrackName: "Song", ArtistName: "A & B"},
+		}))
+	})
+
+	It("should try the normalized tags after the raw tags", func() {
+		metadata := &music.Metadata{
+			Title:       ptr("Song (2011 Remaster)"),
+			Artist:      ptr("A & B"),
+			AlbumArtist: ptr("A & B"),
+			Album:       ptr("Album (Deluxe Edition)"),
+		}
+
+		normalizer := music.NewNormalizer(music.DefaultNormalizationRules())
+		Expect(metadata.SearchQueries(normalizer)).To(Equal([]music.SearchQuery{
+			{TrackName: "Song (2011 Remaster)", ArtistName: "A & B", AlbumName: "Album (Deluxe Edition)"},
+			{TrackName: "Song", ArtistName: "A & B", AlbumName: "Album"},
+			{TrackName: "Song", ArtistName: "A and B", AlbumName: "Album"},
+			{TrackName: "Song", ArtistName: "A & B"},
+			{TrackName: "Song", ArtistName: "A and B"},
+		}))
+	})
+
+	It("should cap the normalized queries", func() {
+		metadata := &music.Metadata{
+			Title:       ptr("Song (2011 Remaster)"),
+			Artist:      ptr("A & B"),
+			AlbumArtist: ptr("A & B"),
+			Album:       ptr("Album (Deluxe Edition)"),
+		}
+
+		rules := music.DefaultNormalizationRules()
+		rules.MaxQueries = 2
+		Expect(metadata.SearchQueries(music.NewNormalizer(rules))).To(Equal([]music.SearchQuery{
+			{TrackName: "Song (2011 Remaster)", ArtistName: "A & B", AlbumName: "Album (Deluxe Edition)"},
+			{TrackName: "Song", ArtistName: "A & B", AlbumName: "Album"},
+			{TrackName: "Song", ArtistName: "A and B", AlbumName: "Album"},
+		}))
+	})
+
+	It("should return no query without a title or an artist", func() {
+		Expect((&music.Metadata{Title: ptr("Vore"), Artist: ptr("")}).SearchQueries(nil)).To(BeEmpty())
+		Expect((&music.Metadata{Artist: ptr("Sleep Token")}).SearchQueries(nil)).To(BeEmpty())
+	})
+})
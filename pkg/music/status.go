@@ -0,0 +1,125 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// LyricsStatus tells where a track stands in the search for its lyrics.
+type LyricsStatus string
+
+const (
+	// LyricsStatusPending is used for tracks whose lyrics were not searched yet.
+	LyricsStatusPending LyricsStatus = "pending"
+	// LyricsStatusSearching is used while the lyrics of the track are being searched.
+	LyricsStatusSearching LyricsStatus = "searching"
+	// LyricsStatusFound is used for tracks with both plain and synced lyrics.
+	LyricsStatusFound LyricsStatus = "found"
+	// LyricsStatusPartial is used for tracks with either plain or synced lyrics.
+	LyricsStatusPartial LyricsStatus = "partial"
+	// LyricsStatusNotFound is used for tracks the provider has no lyrics for.
+	LyricsStatusNotFound LyricsStatus = "not_found"
+	// LyricsStatusInstrumental is used for tracks without lyrics to find.
+	LyricsStatusInstrumental LyricsStatus = "instrumental"
+	// LyricsStatusManual is used for tracks whose lyrics were edited by the user.
+	LyricsStatusManual LyricsStatus = "manual"
+	// LyricsStatusIgnored is used for tracks the user does not want lyrics for.
+	LyricsStatusIgnored LyricsStatus = "ignored"
+	// LyricsStatusError is used for tracks whose lyrics could not be searched.
+	LyricsStatusError LyricsStatus = "error"
+)
+
+var (
+	ErrInvalidLyricsStatus = errors.New("invalid lyrics status")
+	// ErrInvalidLyricsStatusTransition is returned when a track can't go from its status to another.
+	ErrInvalidLyricsStatusTransition = errors.New("invalid lyrics status transition")
+)
+
+// lyricsStatusTransitions lists the statuses a track can go to from each status.
+// Ignored tracks are left alone until the user asks for their lyrics again.
+var lyricsStatusTransitions = map[LyricsStatus][]LyricsStatus{
+	LyricsStatusPending: {
+		LyricsStatusSearching, LyricsStatusFound, LyricsStatusPartial, LyricsStatusInstrumental,
+		LyricsStatusManual, LyricsStatusIgnored, LyricsStatusError,
+	},
+	LyricsStatusSearching: {
+		LyricsStatusPending, LyricsStatusFound, LyricsStatusPartial, LyricsStatusNotFound,
+		LyricsStatusInstrumental, LyricsStatusManual, LyricsStatusIgnored, LyricsStatusError,
+	},
+	LyricsStatusFound: {
+		LyricsStatusPending, LyricsStatusSearching, LyricsStatusPartial, LyricsStatusInstrumental,
+		LyricsStatusManual, LyricsStatusIgnored,
+	},
+	LyricsStatusPartial: {
+		LyricsStatusPending, LyricsStatusSearching, LyricsStatusFound, LyricsStatusInstrumental,
+		LyricsStatusManual, LyricsStatusIgnored,
+	},
+	LyricsStatusNotFound: {
+		LyricsStatusPending, LyricsStatusSearching, LyricsStatusFound, LyricsStatusPartial,
+		LyricsStatusInstrumental, LyricsStatusManual, LyricsStatusIgnored,
+	},
+	LyricsStatusInstrumental: {
+		LyricsStatusPending, LyricsStatusFound, LyricsStatusPartial, LyricsStatusManual,
+		LyricsStatusIgnored,
+	},
+	LyricsStatusManual: {
+		LyricsStatusPending, LyricsStatusFound, LyricsStatusPartial, LyricsStatusInstrumental,
+		LyricsStatusIgnored,
+	},
+	LyricsStatusIgnored: {
+		LyricsStatusPending,
+	},
+	LyricsStatusError: {
+		LyricsStatusPending, LyricsStatusSearching, LyricsStatusFound, LyricsStatusPartial,
+		LyricsStatusInstrumental, LyricsStatusManual, LyricsStatusIgnored,
+	},
+}
+
+// ParseLyricsStatus returns the lyrics status named s.
+func ParseLyricsStatus(s string) (LyricsStatus, error) {
+	status := LyricsStatus(s)
+	if _, ok := lyricsStatusTransitions[status]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidLyricsStatus, s)
+	}
+	return status, nil
+}
+
+// CanTransitionTo reports whether a track can go from status s to next. Staying in the same status
+// is always allowed.
+func (s LyricsStatus) CanTransitionTo(next LyricsStatus) bool {
+	return s == next || slices.Contains(lyricsStatusTransitions[s], next)
+}
+
+// LyricsStatusOf returns the status of a track from the lyrics it has, pending when it has none.
+func LyricsStatusOf(hasPlainLyrics, hasSyncedLyrics bool) LyricsStatus {
+	switch {
+	case hasPlainLyrics && hasSyncedLyrics:
+		return LyricsStatusFound
+	case hasPlainLyrics || hasSyncedLyrics:
+		return LyricsStatusPartial
+	}
+	return LyricsStatusPending
+}
+
+// ResolveLyricsStatus returns the status of a track in status current once its files were read.
+// Ignored tracks stay ignored, and tracks without lyrics keep the outcome of their last search.
+func ResolveLyricsStatus(current LyricsStatus, instrumental, edited, hasPlainLyrics, hasSyncedLyrics bool) LyricsStatus {
+	switch {
+	case current == LyricsStatusIgnored:
+		return current
+	case instrumental:
+		return LyricsStatusInstrumental
+	case edited && (hasPlainLyrics || hasSyncedLyrics):
+		return LyricsStatusManual
+	}
+
+	status := LyricsStatusOf(hasPlainLyrics, hasSyncedLyrics)
+	if status == LyricsStatusPending {
+		switch current {
+		case LyricsStatusSearching, LyricsStatusNotFound, LyricsStatusError:
+			return current
+		}
+	}
+	return status
+}
@@ -0,0 +1,39 @@
+package music_test
+
+import (
+	"github.com/gerald-lbn/refrain/pkg/music"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Status", func() {
+	It("should parse known statuses only", func() {
+		status, err := music.ParseLyricsStatus("not_found")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(music.LyricsStatusNotFound))
+
+		_, err = music.ParseLyricsStatus("lost")
+		Expect(err).To(MatchError(music.ErrInvalidLyricsStatus))
+	})
+
+	It("should only let ignored tracks be searched again", func() {
+		Expect(music.LyricsStatusIgnored.CanTransitionTo(music.LyricsStatusPending)).To(BeTrue())
+		Expect(music.LyricsStatusIgnored.CanTransitionTo(music.LyricsStatusSearching)).To(BeFalse())
+		Expect(music.LyricsStatusIgnored.CanTransitionTo(music.LyricsStatusFound)).To(BeFalse())
+		Expect(music.LyricsStatusFound.CanTransitionTo(music.LyricsStatusNotFound)).To(BeFalse())
+		Expect(music.LyricsStatusSearching.CanTransitionTo(music.LyricsStatusNotFound)).To(BeTrue())
+	})
+
+	DescribeTable("should resolve the status of a track from its files",
+		func(current music.LyricsStatus, instrumental, edited, plain, synced bool, expected music.LyricsStatus) {
+			Expect(music.ResolveLyricsStatus(current, instrumental, edited, plain, synced)).To(Equal(expected))
+		},
+		Entry("new track without lyrics", music.LyricsStatusPending, false, false, false, false, music.LyricsStatusPending),
+		Entry("both lyrics", music.LyricsStatusSearching, false, false, true, true, music.LyricsStatusFound),
+		Entry("synced lyrics only", music.LyricsStatusPending, false, false, false, true, music.LyricsStatusPartial),
+		Entry("lyrics edited by the user", music.LyricsStatusFound, false, true, true, true, music.LyricsStatusManual),
+		Entry("instrumental track", music.LyricsStatusNotFound, true, false, false, false, music.LyricsStatusInstrumental),
+		Entry("unsuccessful search", music.LyricsStatusNotFound, false, false, false, false, music.LyricsStatusNotFound),
+		Entry("ignored track", music.LyricsStatusIgnored, true, false, true, true, music.LyricsStatusIgnored),
+	)
+})
@@ -0,0 +1,129 @@
+package music
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/utils/file"
+	"go.senan.xyz/taglib"
+)
+
+// SYNCED_LYRICS_TAG is the tag holding synced lyrics in formats accepting free-form keys.
+const SYNCED_LYRICS_TAG = "SYNCEDLYRICS"
+
+// freeFormTagsExtensions lists the extensions of formats whose tags (Vorbis comments, APE)
+// accept arbitrary keys, allowing synced lyrics to be stored next to the plain ones.
+var freeFormTagsExtensions = map[string]bool{
+	".flac": true,
+	".oga":  true,
+	".ogg":  true,
+	".opus": true,
+	".spx":  true,
+	".ape":  true,
+	".mpc":  true,
+	".wv":   true,
+}
+
+// EmbedLyricsOptions configures how lyrics are embedded into audio tags.
+type EmbedLyricsOptions struct {
+	// DryRun computes the tags to write without modifying the audio file
+	DryRun bool
+	// BackupDirectory is where the original tags are saved before the first write.
+	// No backup is made when empty.
+	BackupDirectory string
+}
+
+// TagsBackup is the content of a backup made before modifying the tags of an audio file.
+type TagsBackup struct {
+	Path       string              `json:"path"`
+	Tags       map[string][]string `json:"tags"`
+	BackedUpAt time.Time           `json:"backed_up_at"`
+}
+
+// EmbedLyrics writes plain and synced lyrics into the tags of the audio file at p.
+// Plain lyrics go to the LYRICS tag. Synced lyrics go to SYNCED_LYRICS_TAG when the format
+// supports free-form keys, or to LYRICS when there are no plain lyrics to store.
+// It returns the tags which were written, or would have been written on a dry run.
+func EmbedLyrics(p, plain, synced string, opts EmbedLyricsOptions) (map[string][]string, error) {
+	tags := make(map[string][]string)
+	if plain != "" {
+		tags[taglib.Lyrics] = []string{plain}
+	}
+	if synced != "" {
+		if freeFormTagsExtensions[strings.ToLower(filepath.Ext(p))] {
+			tags[SYNCED_LYRICS_TAG] = []string{synced}
+		} else if plain == "" {
+			tags[taglib.Lyrics] = []string{synced}
+		}
+	}
+
+	if len(tags) == 0 || opts.DryRun {
+		return tags, nil
+	}
+
+	if opts.BackupDirectory != "" {
+		if err := backupTags(p, opts.BackupDirectory); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := taglib.WriteTags(p, tags, 0); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// TagsBackupPath returns the path of the tags backup of the audio file at p.
+func TagsBackupPath(p, dir string) (string, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha1.Sum([]byte(abs))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// backupTags saves the current tags of the audio file at p into dir.
+// An existing backup is kept as is so it always holds the original tags.
+func backupTags(p, dir string) error {
+	backupPath, err := TagsBackupPath(p, dir)
+	if err != nil {
+		return err
+	}
+
+	if file.Exists(backupPath) {
+		return nil
+	}
+
+	tags, err := taglib.ReadTags(p)
+	if err != nil {
+		return err
+	}
+
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(TagsBackup{
+		Path:       abs,
+		Tags:       tags,
+		BackedUpAt: time.Now(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(backupPath, content, 0644)
+}
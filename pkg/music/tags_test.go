@@ -0,0 +1,77 @@
+package music_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.senan.xyz/taglib"
+)
+
+var _ = Describe("Tags", func() {
+	var (
+		audioPath string
+		backupDir string
+	)
+
+	const (
+		plainLyrics  = "You have become the voice in my head"
+		syncedLyrics = "[00:15.27] You have become the voice in my head"
+	)
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+		audioPath = copyFile("../test_data/Vore.flac", dir)
+		backupDir = filepath.Join(dir, "backup")
+	})
+
+	When("embedding lyrics", func() {
+		It("should write plain and synced lyrics into the tags", func() {
+			tags, err := music.EmbedLyrics(audioPath, plainLyrics, syncedLyrics, music.EmbedLyricsOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tags).To(HaveKeyWithValue(taglib.Lyrics, []string{plainLyrics}))
+			Expect(tags).To(HaveKeyWithValue(music.SYNCED_LYRICS_TAG, []string{syncedLyrics}))
+
+			metadata, err := music.ExtractMetadata(audioPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metadata.EmbeddedPlainLyrics).To(Equal(plainLyrics))
+			Expect(metadata.EmbeddedSyncedLyrics).To(Equal(syncedLyrics))
+			Expect(*metadata.Title).To(Equal("Vore"))
+		})
+
+		It("should leave the file untouched on a dry run", func() {
+			tags, err := music.EmbedLyrics(audioPath, plainLyrics, syncedLyrics, music.EmbedLyricsOptions{
+				DryRun:          true,
+				BackupDirectory: backupDir,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tags).To(HaveLen(2))
+
+			metadata, err := music.ExtractMetadata(audioPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metadata.HasEmbeddedLyrics()).To(BeFalse())
+			Expect(backupDir).ToNot(BeADirectory())
+		})
+
+		It("should back up the original tags before writing", func() {
+			_, err := music.EmbedLyrics(audioPath, plainLyrics, "", music.EmbedLyricsOptions{
+				BackupDirectory: backupDir,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			backupPath, err := music.TagsBackupPath(audioPath, backupDir)
+			Expect(err).ToNot(HaveOccurred())
+
+			content, err := os.ReadFile(backupPath)
+			Expect(err).ToNot(HaveOccurred())
+
+			var backup music.TagsBackup
+			Expect(json.Unmarshal(content, &backup)).To(Succeed())
+			Expect(backup.Tags).To(HaveKeyWithValue(taglib.Title, []string{"Vore"}))
+			Expect(backup.Tags).ToNot(HaveKey(taglib.Lyrics))
+		})
+	})
+})
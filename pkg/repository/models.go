@@ -6,15 +6,98 @@ package repository
 
 import (
 	"database/sql"
+	"time"
 )
 
+type Album struct {
+	ID            int64          `json:"id"`
+	Title         string         `json:"title"`
+	ArtistID      sql.NullInt64  `json:"artist_id"`
+	MusicbrainzID sql.NullString `json:"musicbrainz_id"`
+	Year          sql.NullInt64  `json:"year"`
+}
+
+type Artist struct {
+	ID            int64          `json:"id"`
+	Name          string         `json:"name"`
+	MusicbrainzID sql.NullString `json:"musicbrainz_id"`
+}
+
+type Lyric struct {
+	TrackID      int64          `json:"track_id"`
+	PlainLyrics  sql.NullString `json:"plain_lyrics"`
+	SyncedLyrics sql.NullString `json:"synced_lyrics"`
+	Provider     string         `json:"provider"`
+	ProviderID   sql.NullInt64  `json:"provider_id"`
+	FetchedAt    time.Time      `json:"fetched_at"`
+	ContentHash  string         `json:"content_hash"`
+	Edited       bool           `json:"edited"`
+}
+
+type LyricsAttempt struct {
+	ID          int64          `json:"id"`
+	TrackID     int64          `json:"track_id"`
+	AttemptedAt time.Time      `json:"attempted_at"`
+	Provider    string         `json:"provider"`
+	TrackName   sql.NullString `json:"track_name"`
+	ArtistName  sql.NullString `json:"artist_name"`
+	AlbumName   sql.NullString `json:"album_name"`
+	Duration    sql.NullInt64  `json:"duration"`
+	Outcome     string         `json:"outcome"`
+	Error       sql.NullString `json:"error"`
+	ProviderID  sql.NullInt64  `json:"provider_id"`
+}
+
+type StatsSnapshot struct {
+	ID                     int64     `json:"id"`
+	Day                    string    `json:"day"`
+	Library                string    `json:"library"`
+	TotalTracks            int64     `json:"total_tracks"`
+	TracksWithSyncedLyrics int64     `json:"tracks_with_synced_lyrics"`
+	TracksWithPlainLyrics  int64     `json:"tracks_with_plain_lyrics"`
+	InstrumentalTracks     int64     `json:"instrumental_tracks"`
+	TracksMissingMetadata  int64     `json:"tracks_missing_metadata"`
+	RecordedAt             time.Time `json:"recorded_at"`
+}
+
+type TrackArtist struct {
+	TrackID  int64  `json:"track_id"`
+	Position int64  `json:"position"`
+	Artist   string `json:"artist"`
+}
+
 type Track struct {
-	ID              int64          `json:"id"`
-	Path            string         `json:"path"`
-	Title           sql.NullString `json:"title"`
-	Artist          sql.NullString `json:"artist"`
-	Album           sql.NullString `json:"album"`
-	Duration        float64        `json:"duration"`
-	HasPlainLyrics  bool           `json:"has_plain_lyrics"`
-	HasSyncedLyrics bool           `json:"has_synced_lyrics"`
+	ID                     int64          `json:"id"`
+	Path                   string         `json:"path"`
+	Title                  sql.NullString `json:"title"`
+	Artist                 sql.NullString `json:"artist"`
+	Album                  sql.NullString `json:"album"`
+	Duration               float64        `json:"duration"`
+	HasPlainLyrics         bool           `json:"has_plain_lyrics"`
+	HasSyncedLyrics        bool           `json:"has_synced_lyrics"`
+	LyricsOffset           int64          `json:"lyrics_offset"`
+	LyricsIssue            sql.NullString `json:"lyrics_issue"`
+	TrackNumber            sql.NullInt64  `json:"track_number"`
+	DiscNumber             sql.NullInt64  `json:"disc_number"`
+	ReleaseDate            sql.NullString `json:"release_date"`
+	Year                   sql.NullInt64  `json:"year"`
+	Genre                  sql.NullString `json:"genre"`
+	Composer               sql.NullString `json:"composer"`
+	MusicbrainzRecordingID sql.NullString `json:"musicbrainz_recording_id"`
+	MusicbrainzReleaseID   sql.NullString `json:"musicbrainz_release_id"`
+	Isrc                   sql.NullString `json:"isrc"`
+	AlbumArtist            sql.NullString `json:"album_artist"`
+	Format                 sql.NullString `json:"format"`
+	Bitrate                sql.NullInt64  `json:"bitrate"`
+	SampleRate             sql.NullInt64  `json:"sample_rate"`
+	Channels               sql.NullInt64  `json:"channels"`
+	FileSize               sql.NullInt64  `json:"file_size"`
+	AudioPath              sql.NullString `json:"audio_path"`
+	CueSheetPath           sql.NullString `json:"cue_sheet_path"`
+	StartOffset            float64        `json:"start_offset"`
+	Instrumental           bool           `json:"instrumental"`
+	InstrumentalSource     sql.NullString `json:"instrumental_source"`
+	ArtistID               sql.NullInt64  `json:"artist_id"`
+	AlbumID                sql.NullInt64  `json:"album_id"`
+	LyricsStatus           string         `json:"lyrics_status"`
 }
@@ -15,21 +15,57 @@ INSERT INTO tracks (
     path,
     title,
     artist,
+    album_artist,
     album,
     duration,
     has_plain_lyrics,
-    has_synced_lyrics
-) VALUES (?, ?, ?, ?, ?, ?, ?)
+    has_synced_lyrics,
+    track_number,
+    disc_number,
+    release_date,
+    year,
+    genre,
+    composer,
+    musicbrainz_recording_id,
+    musicbrainz_release_id,
+    isrc,
+    format,
+    bitrate,
+    sample_rate,
+    channels,
+    file_size,
+    audio_path,
+    cue_sheet_path,
+    start_offset
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateTrackParams struct {
-	Path            string         `json:"path"`
-	Title           sql.NullString `json:"title"`
-	Artist          sql.NullString `json:"artist"`
-	Album           sql.NullString `json:"album"`
-	Duration        float64        `json:"duration"`
-	HasPlainLyrics  bool           `json:"has_plain_lyrics"`
-	HasSyncedLyrics bool           `json:"has_synced_lyrics"`
+	Path                   string         `json:"path"`
+	Title                  sql.NullString `json:"title"`
+	Artist                 sql.NullString `json:"artist"`
+	AlbumArtist            sql.NullString `json:"album_artist"`
+	Album                  sql.NullString `json:"album"`
+	Duration               float64        `json:"duration"`
+	HasPlainLyrics         bool           `json:"has_plain_lyrics"`
+	HasSyncedLyrics        bool           `json:"has_synced_lyrics"`
+	TrackNumber            sql.NullInt64  `json:"track_number"`
+	DiscNumber             sql.NullInt64  `json:"disc_number"`
+	ReleaseDate            sql.NullString `json:"release_date"`
+	Year                   sql.NullInt64  `json:"year"`
+	Genre                  sql.NullString `json:"genre"`
+	Composer               sql.NullString `json:"composer"`
+	MusicbrainzRecordingID sql.NullString `json:"musicbrainz_recording_id"`
+	MusicbrainzReleaseID   sql.NullString `json:"musicbrainz_release_id"`
+	Isrc                   sql.NullString `json:"isrc"`
+	Format                 sql.NullString `json:"format"`
+	Bitrate                sql.NullInt64  `json:"bitrate"`
+	SampleRate             sql.NullInt64  `json:"sample_rate"`
+	Channels               sql.NullInt64  `json:"channels"`
+	FileSize               sql.NullInt64  `json:"file_size"`
+	AudioPath              sql.NullString `json:"audio_path"`
+	CueSheetPath           sql.NullString `json:"cue_sheet_path"`
+	StartOffset            float64        `json:"start_offset"`
 }
 
 func (q *Queries) CreateTrack(ctx context.Context, arg CreateTrackParams) error {
@@ -37,14 +73,46 @@ func (q *Queries) CreateTrack(ctx context.Context, arg CreateTrackParams) error
 		arg.Path,
 		arg.Title,
 		arg.Artist,
+		arg.AlbumArtist,
 		arg.Album,
 		arg.Duration,
 		arg.HasPlainLyrics,
 		arg.HasSyncedLyrics,
+		arg.TrackNumber,
+		arg.DiscNumber,
+		arg.ReleaseDate,
+		arg.Year,
+		arg.Genre,
+		arg.Composer,
+		arg.MusicbrainzRecordingID,
+		arg.MusicbrainzReleaseID,
+		arg.Isrc,
+		arg.Format,
+		arg.Bitrate,
+		arg.SampleRate,
+		arg.Channels,
+		arg.FileSize,
+		arg.AudioPath,
+		arg.CueSheetPath,
+		arg.StartOffset,
 	)
 	return err
 }
 
+const deleteCueSheetTracks = `-- name: DeleteCueSheetTracks :exec
+DELETE FROM tracks WHERE audio_path = ? OR cue_sheet_path = ?
+`
+
+type DeleteCueSheetTracksParams struct {
+	AudioPath    sql.NullString `json:"audio_path"`
+	CueSheetPath sql.NullString `json:"cue_sheet_path"`
+}
+
+func (q *Queries) DeleteCueSheetTracks(ctx context.Context, arg DeleteCueSheetTracksParams) error {
+	_, err := q.db.ExecContext(ctx, deleteCueSheetTracks, arg.AudioPath, arg.CueSheetPath)
+	return err
+}
+
 const deleteTrack = `-- name: DeleteTrack :exec
 DELETE FROM tracks WHERE path = ?
 `
@@ -58,24 +126,75 @@ const getAllTracks = `-- name: GetAllTracks :many
 SELECT
     id,
     path,
-    CAST(title AS TEXT),
-    CAST(artist AS TEXT),
-    CAST(album AS TEXT),
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
     duration,
     has_plain_lyrics,
-    has_synced_lyrics
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
 FROM tracks
+ORDER BY artist, album, disc_number, track_number, path
 `
 
 type GetAllTracksRow struct {
-	ID              int64   `json:"id"`
-	Path            string  `json:"path"`
-	Title           string  `json:"title"`
-	Artist          string  `json:"artist"`
-	Album           string  `json:"album"`
-	Duration        float64 `json:"duration"`
-	HasPlainLyrics  bool    `json:"has_plain_lyrics"`
-	HasSyncedLyrics bool    `json:"has_synced_lyrics"`
+	ID                     int64   `json:"id"`
+	Path                   string  `json:"path"`
+	Title                  string  `json:"title"`
+	Artist                 string  `json:"artist"`
+	AlbumArtist            string  `json:"album_artist"`
+	Album                  string  `json:"album"`
+	Duration               float64 `json:"duration"`
+	HasPlainLyrics         bool    `json:"has_plain_lyrics"`
+	HasSyncedLyrics        bool    `json:"has_synced_lyrics"`
+	LyricsOffset           int64   `json:"lyrics_offset"`
+	LyricsIssue            string  `json:"lyrics_issue"`
+	TrackNumber            int64   `json:"track_number"`
+	DiscNumber             int64   `json:"disc_number"`
+	ReleaseDate            string  `json:"release_date"`
+	Year                   int64   `json:"year"`
+	Genre                  string  `json:"genre"`
+	Composer               string  `json:"composer"`
+	MusicbrainzRecordingID string  `json:"musicbrainz_recording_id"`
+	MusicbrainzReleaseID   string  `json:"musicbrainz_release_id"`
+	Isrc                   string  `json:"isrc"`
+	Format                 string  `json:"format"`
+	Bitrate                int64   `json:"bitrate"`
+	SampleRate             int64   `json:"sample_rate"`
+	Channels               int64   `json:"channels"`
+	FileSize               int64   `json:"file_size"`
+	AudioPath              string  `json:"audio_path"`
+	CueSheetPath           string  `json:"cue_sheet_path"`
+	StartOffset            float64 `json:"start_offset"`
+	Instrumental           bool    `json:"instrumental"`
+	InstrumentalSource     string  `json:"instrumental_source"`
+	ArtistID               int64   `json:"artist_id"`
+	AlbumID                int64   `json:"album_id"`
+	LyricsStatus           string  `json:"lyrics_status"`
 }
 
 func (q *Queries) GetAllTracks(ctx context.Context) ([]GetAllTracksRow, error) {
@@ -92,10 +211,84 @@ func (q *Queries) GetAllTracks(ctx context.Context) ([]GetAllTracksRow, error) {
 			&i.Path,
 			&i.Title,
 			&i.Artist,
+			&i.AlbumArtist,
 			&i.Album,
 			&i.Duration,
 			&i.HasPlainLyrics,
 			&i.HasSyncedLyrics,
+			&i.LyricsOffset,
+			&i.LyricsIssue,
+			&i.TrackNumber,
+			&i.DiscNumber,
+			&i.ReleaseDate,
+			&i.Year,
+			&i.Genre,
+			&i.Composer,
+			&i.MusicbrainzRecordingID,
+			&i.MusicbrainzReleaseID,
+			&i.Isrc,
+			&i.Format,
+			&i.Bitrate,
+			&i.SampleRate,
+			&i.Channels,
+			&i.FileSize,
+			&i.AudioPath,
+			&i.CueSheetPath,
+			&i.StartOffset,
+			&i.Instrumental,
+			&i.InstrumentalSource,
+			&i.ArtistID,
+			&i.AlbumID,
+			&i.LyricsStatus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFormatStats = `-- name: GetFormatStats :many
+SELECT
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    COUNT(*) AS total_tracks,
+    CAST(COALESCE(SUM(file_size), 0) AS INTEGER) AS total_file_size,
+    CAST(COALESCE(AVG(bitrate), 0) AS INTEGER) AS average_bitrate,
+    CAST(COALESCE(AVG(sample_rate), 0) AS INTEGER) AS average_sample_rate
+FROM tracks
+GROUP BY format
+ORDER BY total_tracks DESC, format
+`
+
+type GetFormatStatsRow struct {
+	Format            string `json:"format"`
+	TotalTracks       int64  `json:"total_tracks"`
+	TotalFileSize     int64  `json:"total_file_size"`
+	AverageBitrate    int64  `json:"average_bitrate"`
+	AverageSampleRate int64  `json:"average_sample_rate"`
+}
+
+func (q *Queries) GetFormatStats(ctx context.Context) ([]GetFormatStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFormatStats)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFormatStatsRow
+	for rows.Next() {
+		var i GetFormatStatsRow
+		if err := rows.Scan(
+			&i.Format,
+			&i.TotalTracks,
+			&i.TotalFileSize,
+			&i.AverageBitrate,
+			&i.AverageSampleRate,
 		); err != nil {
 			return nil, err
 		}
@@ -116,13 +309,25 @@ SELECT
     CAST(SUM(CASE WHEN has_plain_lyrics = 1 AND has_synced_lyrics = 1 THEN 1 ELSE 0 END) AS INTEGER) AS tracks_with_both_lyrics,
     CAST(SUM(CASE WHEN has_plain_lyrics = 0 AND has_synced_lyrics = 1 THEN 1 ELSE 0 END) AS INTEGER) AS tracks_with_synced_only,
     CAST(SUM(CASE WHEN has_plain_lyrics = 1 AND has_synced_lyrics = 0 THEN 1 ELSE 0 END) AS INTEGER) AS tracks_with_plain_only,
-    CAST(SUM(CASE WHEN has_plain_lyrics = 0 AND has_synced_lyrics = 0 THEN 1 ELSE 0 END) AS INTEGER) AS instrumental_tracks,
+    CAST(SUM(CASE WHEN instrumental = 1 THEN 1 ELSE 0 END) AS INTEGER) AS instrumental_tracks,
+    CAST(SUM(CASE WHEN has_plain_lyrics = 0 AND has_synced_lyrics = 0 AND instrumental = 0 THEN 1 ELSE 0 END) AS INTEGER) AS tracks_without_lyrics,
     CAST(SUM(
         CASE WHEN title IS NULL OR title = ''
               OR artist IS NULL OR artist = ''
               OR album IS NULL OR album = ''
         THEN 1 ELSE 0 END
-    ) AS INTEGER) AS tracks_missing_metadata
+    ) AS INTEGER) AS tracks_missing_metadata,
+    CAST(COALESCE(SUM(file_size), 0) AS INTEGER) AS total_file_size,
+    CAST(COALESCE(AVG(bitrate), 0) AS INTEGER) AS average_bitrate,
+    CAST(SUM(CASE WHEN lyrics_status = 'pending' THEN 1 ELSE 0 END) AS INTEGER) AS status_pending,
+    CAST(SUM(CASE WHEN lyrics_status = 'searching' THEN 1 ELSE 0 END) AS INTEGER) AS status_searching,
+    CAST(SUM(CASE WHEN lyrics_status = 'found' THEN 1 ELSE 0 END) AS INTEGER) AS status_found,
+    CAST(SUM(CASE WHEN lyrics_status = 'partial' THEN 1 ELSE 0 END) AS INTEGER) AS status_partial,
+    CAST(SUM(CASE WHEN lyrics_status = 'not_found' THEN 1 ELSE 0 END) AS INTEGER) AS status_not_found,
+    CAST(SUM(CASE WHEN lyrics_status = 'instrumental' THEN 1 ELSE 0 END) AS INTEGER) AS status_instrumental,
+    CAST(SUM(CASE WHEN lyrics_status = 'manual' THEN 1 ELSE 0 END) AS INTEGER) AS status_manual,
+    CAST(SUM(CASE WHEN lyrics_status = 'ignored' THEN 1 ELSE 0 END) AS INTEGER) AS status_ignored,
+    CAST(SUM(CASE WHEN lyrics_status = 'error' THEN 1 ELSE 0 END) AS INTEGER) AS status_error
 FROM tracks
 `
 
@@ -132,7 +337,19 @@ type GetStatsRow struct {
 	TracksWithSyncedOnly  int64 `json:"tracks_with_synced_only"`
 	TracksWithPlainOnly   int64 `json:"tracks_with_plain_only"`
 	InstrumentalTracks    int64 `json:"instrumental_tracks"`
+	TracksWithoutLyrics   int64 `json:"tracks_without_lyrics"`
 	TracksMissingMetadata int64 `json:"tracks_missing_metadata"`
+	TotalFileSize         int64 `json:"total_file_size"`
+	AverageBitrate        int64 `json:"average_bitrate"`
+	StatusPending         int64 `json:"status_pending"`
+	StatusSearching       int64 `json:"status_searching"`
+	StatusFound           int64 `json:"status_found"`
+	StatusPartial         int64 `json:"status_partial"`
+	StatusNotFound        int64 `json:"status_not_found"`
+	StatusInstrumental    int64 `json:"status_instrumental"`
+	StatusManual          int64 `json:"status_manual"`
+	StatusIgnored         int64 `json:"status_ignored"`
+	StatusError           int64 `json:"status_error"`
 }
 
 func (q *Queries) GetStats(ctx context.Context) (GetStatsRow, error) {
@@ -144,7 +361,19 @@ func (q *Queries) GetStats(ctx context.Context) (GetStatsRow, error) {
 		&i.TracksWithSyncedOnly,
 		&i.TracksWithPlainOnly,
 		&i.InstrumentalTracks,
+		&i.TracksWithoutLyrics,
 		&i.TracksMissingMetadata,
+		&i.TotalFileSize,
+		&i.AverageBitrate,
+		&i.StatusPending,
+		&i.StatusSearching,
+		&i.StatusFound,
+		&i.StatusPartial,
+		&i.StatusNotFound,
+		&i.StatusInstrumental,
+		&i.StatusManual,
+		&i.StatusIgnored,
+		&i.StatusError,
 	)
 	return i, err
 }
@@ -153,26 +382,76 @@ const getTrackByID = `-- name: GetTrackByID :one
 SELECT
     id,
     path,
-    CAST(title AS TEXT),
-    CAST(artist AS TEXT),
-    CAST(album AS TEXT),
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
     duration,
     has_plain_lyrics,
-    has_synced_lyrics
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
 FROM tracks
 WHERE id = ?
 LIMIT 1
 `
 
 type GetTrackByIDRow struct {
-	ID              int64   `json:"id"`
-	Path            string  `json:"path"`
-	Title           string  `json:"title"`
-	Artist          string  `json:"artist"`
-	Album           string  `json:"album"`
-	Duration        float64 `json:"duration"`
-	HasPlainLyrics  bool    `json:"has_plain_lyrics"`
-	HasSyncedLyrics bool    `json:"has_synced_lyrics"`
+	ID                     int64   `json:"id"`
+	Path                   string  `json:"path"`
+	Title                  string  `json:"title"`
+	Artist                 string  `json:"artist"`
+	AlbumArtist            string  `json:"album_artist"`
+	Album                  string  `json:"album"`
+	Duration               float64 `json:"duration"`
+	HasPlainLyrics         bool    `json:"has_plain_lyrics"`
+	HasSyncedLyrics        bool    `json:"has_synced_lyrics"`
+	LyricsOffset           int64   `json:"lyrics_offset"`
+	LyricsIssue            string  `json:"lyrics_issue"`
+	TrackNumber            int64   `json:"track_number"`
+	DiscNumber             int64   `json:"disc_number"`
+	ReleaseDate            string  `json:"release_date"`
+	Year                   int64   `json:"year"`
+	Genre                  string  `json:"genre"`
+	Composer               string  `json:"composer"`
+	MusicbrainzRecordingID string  `json:"musicbrainz_recording_id"`
+	MusicbrainzReleaseID   string  `json:"musicbrainz_release_id"`
+	Isrc                   string  `json:"isrc"`
+	Format                 string  `json:"format"`
+	Bitrate                int64   `json:"bitrate"`
+	SampleRate             int64   `json:"sample_rate"`
+	Channels               int64   `json:"channels"`
+	FileSize               int64   `json:"file_size"`
+	AudioPath              string  `json:"audio_path"`
+	CueSheetPath           string  `json:"cue_sheet_path"`
+	StartOffset            float64 `json:"start_offset"`
+	Instrumental           bool    `json:"instrumental"`
+	InstrumentalSource     string  `json:"instrumental_source"`
+	ArtistID               int64   `json:"artist_id"`
+	AlbumID                int64   `json:"album_id"`
+	LyricsStatus           string  `json:"lyrics_status"`
 }
 
 func (q *Queries) GetTrackByID(ctx context.Context, id int64) (GetTrackByIDRow, error) {
@@ -183,10 +462,35 @@ func (q *Queries) GetTrackByID(ctx context.Context, id int64) (GetTrackByIDRow,
 		&i.Path,
 		&i.Title,
 		&i.Artist,
+		&i.AlbumArtist,
 		&i.Album,
 		&i.Duration,
 		&i.HasPlainLyrics,
 		&i.HasSyncedLyrics,
+		&i.LyricsOffset,
+		&i.LyricsIssue,
+		&i.TrackNumber,
+		&i.DiscNumber,
+		&i.ReleaseDate,
+		&i.Year,
+		&i.Genre,
+		&i.Composer,
+		&i.MusicbrainzRecordingID,
+		&i.MusicbrainzReleaseID,
+		&i.Isrc,
+		&i.Format,
+		&i.Bitrate,
+		&i.SampleRate,
+		&i.Channels,
+		&i.FileSize,
+		&i.AudioPath,
+		&i.CueSheetPath,
+		&i.StartOffset,
+		&i.Instrumental,
+		&i.InstrumentalSource,
+		&i.ArtistID,
+		&i.AlbumID,
+		&i.LyricsStatus,
 	)
 	return i, err
 }
@@ -195,26 +499,76 @@ const getTrackByPath = `-- name: GetTrackByPath :one
 SELECT
     id,
     path,
-    CAST(title AS TEXT),
-    CAST(artist AS TEXT),
-    CAST(album AS TEXT),
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
     duration,
     has_plain_lyrics,
-    has_synced_lyrics
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
 FROM tracks
 WHERE path = ?
 LIMIT 1
 `
 
 type GetTrackByPathRow struct {
-	ID              int64   `json:"id"`
-	Path            string  `json:"path"`
-	Title           string  `json:"title"`
-	Artist          string  `json:"artist"`
-	Album           string  `json:"album"`
-	Duration        float64 `json:"duration"`
-	HasPlainLyrics  bool    `json:"has_plain_lyrics"`
-	HasSyncedLyrics bool    `json:"has_synced_lyrics"`
+	ID                     int64   `json:"id"`
+	Path                   string  `json:"path"`
+	Title                  string  `json:"title"`
+	Artist                 string  `json:"artist"`
+	AlbumArtist            string  `json:"album_artist"`
+	Album                  string  `json:"album"`
+	Duration               float64 `json:"duration"`
+	HasPlainLyrics         bool    `json:"has_plain_lyrics"`
+	HasSyncedLyrics        bool    `json:"has_synced_lyrics"`
+	LyricsOffset           int64   `json:"lyrics_offset"`
+	LyricsIssue            string  `json:"lyrics_issue"`
+	TrackNumber            int64   `json:"track_number"`
+	DiscNumber             int64   `json:"disc_number"`
+	ReleaseDate            string  `json:"release_date"`
+	Year                   int64   `json:"year"`
+	Genre                  string  `json:"genre"`
+	Composer               string  `json:"composer"`
+	MusicbrainzRecordingID string  `json:"musicbrainz_recording_id"`
+	MusicbrainzReleaseID   string  `json:"musicbrainz_release_id"`
+	Isrc                   string  `json:"isrc"`
+	Format                 string  `json:"format"`
+	Bitrate                int64   `json:"bitrate"`
+	SampleRate             int64   `json:"sample_rate"`
+	Channels               int64   `json:"channels"`
+	FileSize               int64   `json:"file_size"`
+	AudioPath              string  `json:"audio_path"`
+	CueSheetPath           string  `json:"cue_sheet_path"`
+	StartOffset            float64 `json:"start_offset"`
+	Instrumental           bool    `json:"instrumental"`
+	InstrumentalSource     string  `json:"instrumental_source"`
+	ArtistID               int64   `json:"artist_id"`
+	AlbumID                int64   `json:"album_id"`
+	LyricsStatus           string  `json:"lyrics_status"`
 }
 
 func (q *Queries) GetTrackByPath(ctx context.Context, path string) (GetTrackByPathRow, error) {
@@ -225,27 +579,525 @@ func (q *Queries) GetTrackByPath(ctx context.Context, path string) (GetTrackByPa
 		&i.Path,
 		&i.Title,
 		&i.Artist,
+		&i.AlbumArtist,
 		&i.Album,
 		&i.Duration,
 		&i.HasPlainLyrics,
 		&i.HasSyncedLyrics,
+		&i.LyricsOffset,
+		&i.LyricsIssue,
+		&i.TrackNumber,
+		&i.DiscNumber,
+		&i.ReleaseDate,
+		&i.Year,
+		&i.Genre,
+		&i.Composer,
+		&i.MusicbrainzRecordingID,
+		&i.MusicbrainzReleaseID,
+		&i.Isrc,
+		&i.Format,
+		&i.Bitrate,
+		&i.SampleRate,
+		&i.Channels,
+		&i.FileSize,
+		&i.AudioPath,
+		&i.CueSheetPath,
+		&i.StartOffset,
+		&i.Instrumental,
+		&i.InstrumentalSource,
+		&i.ArtistID,
+		&i.AlbumID,
+		&i.LyricsStatus,
 	)
 	return i, err
 }
 
+const getTracksByAlbum = `-- name: GetTracksByAlbum :many
+SELECT
+    id,
+    path,
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
+    duration,
+    has_plain_lyrics,
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
+FROM tracks
+WHERE album = ?
+  AND COALESCE(album_artist, artist, '') = CAST(? AS TEXT)
+ORDER BY disc_number, track_number, path
+`
+
+type GetTracksByAlbumParams struct {
+	Album       sql.NullString `json:"album"`
+	AlbumArtist string         `json:"album_artist"`
+}
+
+type GetTracksByAlbumRow struct {
+	ID                     int64   `json:"id"`
+	Path                   string  `json:"path"`
+	Title                  string  `json:"title"`
+	Artist                 string  `json:"artist"`
+	AlbumArtist            string  `json:"album_artist"`
+	Album                  string  `json:"album"`
+	Duration               float64 `json:"duration"`
+	HasPlainLyrics         bool    `json:"has_plain_lyrics"`
+	HasSyncedLyrics        bool    `json:"has_synced_lyrics"`
+	LyricsOffset           int64   `json:"lyrics_offset"`
+	LyricsIssue            string  `json:"lyrics_issue"`
+	TrackNumber            int64   `json:"track_number"`
+	DiscNumber             int64   `json:"disc_number"`
+	ReleaseDate            string  `json:"release_date"`
+	Year                   int64   `json:"year"`
+	Genre                  string  `json:"genre"`
+	Composer               string  `json:"composer"`
+	MusicbrainzRecordingID string  `json:"musicbrainz_recording_id"`
+	MusicbrainzReleaseID   string  `json:"musicbrainz_release_id"`
+	Isrc                   string  `json:"isrc"`
+	Format                 string  `json:"format"`
+	Bitrate                int64   `json:"bitrate"`
+	SampleRate             int64   `json:"sample_rate"`
+	Channels               int64   `json:"channels"`
+	FileSize               int64   `json:"file_size"`
+	AudioPath              string  `json:"audio_path"`
+	CueSheetPath           string  `json:"cue_sheet_path"`
+	StartOffset            float64 `json:"start_offset"`
+	Instrumental           bool    `json:"instrumental"`
+	InstrumentalSource     string  `json:"instrumental_source"`
+	ArtistID               int64   `json:"artist_id"`
+	AlbumID                int64   `json:"album_id"`
+	LyricsStatus           string  `json:"lyrics_status"`
+}
+
+func (q *Queries) GetTracksByAlbum(ctx context.Context, arg GetTracksByAlbumParams) ([]GetTracksByAlbumRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTracksByAlbum, arg.Album, arg.AlbumArtist)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTracksByAlbumRow
+	for rows.Next() {
+		var i GetTracksByAlbumRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Path,
+			&i.Title,
+			&i.Artist,
+			&i.AlbumArtist,
+			&i.Album,
+			&i.Duration,
+			&i.HasPlainLyrics,
+			&i.HasSyncedLyrics,
+			&i.LyricsOffset,
+			&i.LyricsIssue,
+			&i.TrackNumber,
+			&i.DiscNumber,
+			&i.ReleaseDate,
+			&i.Year,
+			&i.Genre,
+			&i.Composer,
+			&i.MusicbrainzRecordingID,
+			&i.MusicbrainzReleaseID,
+			&i.Isrc,
+			&i.Format,
+			&i.Bitrate,
+			&i.SampleRate,
+			&i.Channels,
+			&i.FileSize,
+			&i.AudioPath,
+			&i.CueSheetPath,
+			&i.StartOffset,
+			&i.Instrumental,
+			&i.InstrumentalSource,
+			&i.ArtistID,
+			&i.AlbumID,
+			&i.LyricsStatus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTracksByAlbumID = `-- name: GetTracksByAlbumID :many
+SELECT
+    id,
+    path,
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
+    duration,
+    has_plain_lyrics,
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
+FROM tracks
+WHERE album_id = ?
+ORDER BY disc_number, track_number, path
+`
+
+type GetTracksByAlbumIDRow struct {
+	ID                     int64   `json:"id"`
+	Path                   string  `json:"path"`
+	Title                  string  `json:"title"`
+	Artist                 string  `json:"artist"`
+	AlbumArtist            string  `json:"album_artist"`
+	Album                  string  `json:"album"`
+	Duration               float64 `json:"duration"`
+	HasPlainLyrics         bool    `json:"has_plain_lyrics"`
+	HasSyncedLyrics        bool    `json:"has_synced_lyrics"`
+	LyricsOffset           int64   `json:"lyrics_offset"`
+	LyricsIssue            string  `json:"lyrics_issue"`
+	TrackNumber            int64   `json:"track_number"`
+	DiscNumber             int64   `json:"disc_number"`
+	ReleaseDate            string  `json:"release_date"`
+	Year                   int64   `json:"year"`
+	Genre                  string  `json:"genre"`
+	Composer               string  `json:"composer"`
+	MusicbrainzRecordingID string  `json:"musicbrainz_recording_id"`
+	MusicbrainzReleaseID   string  `json:"musicbrainz_release_id"`
+	Isrc                   string  `json:"isrc"`
+	Format                 string  `json:"format"`
+	Bitrate                int64   `json:"bitrate"`
+	SampleRate             int64   `json:"sample_rate"`
+	Channels               int64   `json:"channels"`
+	FileSize               int64   `json:"file_size"`
+	AudioPath              string  `json:"audio_path"`
+	CueSheetPath           string  `json:"cue_sheet_path"`
+	StartOffset            float64 `json:"start_offset"`
+	Instrumental           bool    `json:"instrumental"`
+	InstrumentalSource     string  `json:"instrumental_source"`
+	ArtistID               int64   `json:"artist_id"`
+	AlbumID                int64   `json:"album_id"`
+	LyricsStatus           string  `json:"lyrics_status"`
+}
+
+func (q *Queries) GetTracksByAlbumID(ctx context.Context, albumID sql.NullInt64) ([]GetTracksByAlbumIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTracksByAlbumID, albumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTracksByAlbumIDRow
+	for rows.Next() {
+		var i GetTracksByAlbumIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Path,
+			&i.Title,
+			&i.Artist,
+			&i.AlbumArtist,
+			&i.Album,
+			&i.Duration,
+			&i.HasPlainLyrics,
+			&i.HasSyncedLyrics,
+			&i.LyricsOffset,
+			&i.LyricsIssue,
+			&i.TrackNumber,
+			&i.DiscNumber,
+			&i.ReleaseDate,
+			&i.Year,
+			&i.Genre,
+			&i.Composer,
+			&i.MusicbrainzRecordingID,
+			&i.MusicbrainzReleaseID,
+			&i.Isrc,
+			&i.Format,
+			&i.Bitrate,
+			&i.SampleRate,
+			&i.Channels,
+			&i.FileSize,
+			&i.AudioPath,
+			&i.CueSheetPath,
+			&i.StartOffset,
+			&i.Instrumental,
+			&i.InstrumentalSource,
+			&i.ArtistID,
+			&i.AlbumID,
+			&i.LyricsStatus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTracksByArtist = `-- name: GetTracksByArtist :many
+SELECT
+    id,
+    path,
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
+    duration,
+    has_plain_lyrics,
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
+FROM tracks
+WHERE id IN (
+    SELECT track_id FROM track_artists WHERE track_artists.artist = CAST(? AS TEXT) COLLATE NOCASE
+)
+ORDER BY artist, album, disc_number, track_number, path
+`
+
+type GetTracksByArtistRow struct {
+	ID                     int64   `json:"id"`
+	Path                   string  `json:"path"`
+	Title                  string  `json:"title"`
+	Artist                 string  `json:"artist"`
+	AlbumArtist            string  `json:"album_artist"`
+	Album                  string  `json:"album"`
+	Duration               float64 `json:"duration"`
+	HasPlainLyrics         bool    `json:"has_plain_lyrics"`
+	HasSyncedLyrics        bool    `json:"has_synced_lyrics"`
+	LyricsOffset           int64   `json:"lyrics_offset"`
+	LyricsIssue            string  `json:"lyrics_issue"`
+	TrackNumber            int64   `json:"track_number"`
+	DiscNumber             int64   `json:"disc_number"`
+	ReleaseDate            string  `json:"release_date"`
+	Year                   int64   `json:"year"`
+	Genre                  string  `json:"genre"`
+	Composer               string  `json:"composer"`
+	MusicbrainzRecordingID string  `json:"musicbrainz_recording_id"`
+	MusicbrainzReleaseID   string  `json:"musicbrainz_release_id"`
+	Isrc                   string  `json:"isrc"`
+	Format                 string  `json:"format"`
+	Bitrate                int64   `json:"bitrate"`
+	SampleRate             int64   `json:"sample_rate"`
+	Channels               int64   `json:"channels"`
+	FileSize               int64   `json:"file_size"`
+	AudioPath              string  `json:"audio_path"`
+	CueSheetPath           string  `json:"cue_sheet_path"`
+	StartOffset            float64 `json:"start_offset"`
+	Instrumental           bool    `json:"instrumental"`
+	InstrumentalSource     string  `json:"instrumental_source"`
+	ArtistID               int64   `json:"artist_id"`
+	AlbumID                int64   `json:"album_id"`
+	LyricsStatus           string  `json:"lyrics_status"`
+}
+
+func (q *Queries) GetTracksByArtist(ctx context.Context, artist string) ([]GetTracksByArtistRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTracksByArtist, artist)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTracksByArtistRow
+	for rows.Next() {
+		var i GetTracksByArtistRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Path,
+			&i.Title,
+			&i.Artist,
+			&i.AlbumArtist,
+			&i.Album,
+			&i.Duration,
+			&i.HasPlainLyrics,
+			&i.HasSyncedLyrics,
+			&i.LyricsOffset,
+			&i.LyricsIssue,
+			&i.TrackNumber,
+			&i.DiscNumber,
+			&i.ReleaseDate,
+			&i.Year,
+			&i.Genre,
+			&i.Composer,
+			&i.MusicbrainzRecordingID,
+			&i.MusicbrainzReleaseID,
+			&i.Isrc,
+			&i.Format,
+			&i.Bitrate,
+			&i.SampleRate,
+			&i.Channels,
+			&i.FileSize,
+			&i.AudioPath,
+			&i.CueSheetPath,
+			&i.StartOffset,
+			&i.Instrumental,
+			&i.InstrumentalSource,
+			&i.ArtistID,
+			&i.AlbumID,
+			&i.LyricsStatus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrackPathsWithoutEntities = `-- name: ListTrackPathsWithoutEntities :many
+SELECT path FROM tracks
+WHERE (artist_id IS NULL AND COALESCE(artist, '') != '')
+   OR (album_id IS NULL AND COALESCE(album, '') != '')
+`
+
+func (q *Queries) ListTrackPathsWithoutEntities(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listTrackPathsWithoutEntities)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		items = append(items, path)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resetSearchingLyricsStatuses = `-- name: ResetSearchingLyricsStatuses :execrows
+UPDATE tracks SET lyrics_status = 'pending' WHERE lyrics_status = 'searching'
+`
+
+func (q *Queries) ResetSearchingLyricsStatuses(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, resetSearchingLyricsStatuses)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const searchTracks = `-- name: SearchTracks :many
 SELECT
     id,
     path,
-    CAST(title AS TEXT),
-    CAST(artist AS TEXT),
-    CAST(album AS TEXT),
+    CAST(COALESCE(title, '') AS TEXT) AS title,
+    CAST(COALESCE(artist, '') AS TEXT) AS artist,
+    CAST(COALESCE(album_artist, artist, '') AS TEXT) AS album_artist,
+    CAST(COALESCE(album, '') AS TEXT) AS album,
     duration,
     has_plain_lyrics,
-    has_synced_lyrics
+    has_synced_lyrics,
+    lyrics_offset,
+    CAST(COALESCE(lyrics_issue, '') AS TEXT) AS lyrics_issue,
+    CAST(COALESCE(track_number, 0) AS INTEGER) AS track_number,
+    CAST(COALESCE(disc_number, 0) AS INTEGER) AS disc_number,
+    CAST(COALESCE(release_date, '') AS TEXT) AS release_date,
+    CAST(COALESCE(year, 0) AS INTEGER) AS year,
+    CAST(COALESCE(genre, '') AS TEXT) AS genre,
+    CAST(COALESCE(composer, '') AS TEXT) AS composer,
+    CAST(COALESCE(musicbrainz_recording_id, '') AS TEXT) AS musicbrainz_recording_id,
+    CAST(COALESCE(musicbrainz_release_id, '') AS TEXT) AS musicbrainz_release_id,
+    CAST(COALESCE(isrc, '') AS TEXT) AS isrc,
+    CAST(COALESCE(format, '') AS TEXT) AS format,
+    CAST(COALESCE(bitrate, 0) AS INTEGER) AS bitrate,
+    CAST(COALESCE(sample_rate, 0) AS INTEGER) AS sample_rate,
+    CAST(COALESCE(channels, 0) AS INTEGER) AS channels,
+    CAST(COALESCE(file_size, 0) AS INTEGER) AS file_size,
+    CAST(COALESCE(audio_path, path) AS TEXT) AS audio_path,
+    CAST(COALESCE(cue_sheet_path, '') AS TEXT) AS cue_sheet_path,
+    start_offset,
+    instrumental,
+    CAST(COALESCE(instrumental_source, '') AS TEXT) AS instrumental_source,
+    CAST(COALESCE(artist_id, 0) AS INTEGER) AS artist_id,
+    CAST(COALESCE(album_id, 0) AS INTEGER) AS album_id,
+    lyrics_status
 FROM tracks
 WHERE title LIKE ? OR artist LIKE ? OR album LIKE ?
-ORDER BY artist, album, title
+ORDER BY artist, album, disc_number, track_number, title
 `
 
 type SearchTracksParams struct {
@@ -255,14 +1107,39 @@ type SearchTracksParams struct {
 }
 
 type SearchTracksRow struct {
-	ID              int64   `json:"id"`
-	Path            string  `json:"path"`
-	Title           string  `json:"title"`
-	Artist          string  `json:"artist"`
-	Album           string  `json:"album"`
-	Duration        float64 `json:"duration"`
-	HasPlainLyrics  bool    `json:"has_plain_lyrics"`
-	HasSyncedLyrics bool    `json:"has_synced_lyrics"`
+	ID                     int64   `json:"id"`
+	Path                   string  `json:"path"`
+	Title                  string  `json:"title"`
+	Artist                 string  `json:"artist"`
+	AlbumArtist            string  `json:"album_artist"`
+	Album                  string  `json:"album"`
+	Duration               float64 `json:"duration"`
+	HasPlainLyrics         bool    `json:"has_plain_lyrics"`
+	HasSyncedLyrics        bool    `json:"has_synced_lyrics"`
+	LyricsOffset           int64   `json:"lyrics_offset"`
+	LyricsIssue            string  `json:"lyrics_issue"`
+	TrackNumber            int64   `json:"track_number"`
+	DiscNumber             int64   `json:"disc_number"`
+	ReleaseDate            string  `json:"release_date"`
+	Year                   int64   `json:"year"`
+	Genre                  string  `json:"genre"`
+	Composer               string  `json:"composer"`
+	MusicbrainzRecordingID string  `json:"musicbrainz_recording_id"`
+	MusicbrainzReleaseID   string  `json:"musicbrainz_release_id"`
+	Isrc                   string  `json:"isrc"`
+	Format                 string  `json:"format"`
+	Bitrate                int64   `json:"bitrate"`
+	SampleRate             int64   `json:"sample_rate"`
+	Channels               int64   `json:"channels"`
+	FileSize               int64   `json:"file_size"`
+	AudioPath              string  `json:"audio_path"`
+	CueSheetPath           string  `json:"cue_sheet_path"`
+	StartOffset            float64 `json:"start_offset"`
+	Instrumental           bool    `json:"instrumental"`
+	InstrumentalSource     string  `json:"instrumental_source"`
+	ArtistID               int64   `json:"artist_id"`
+	AlbumID                int64   `json:"album_id"`
+	LyricsStatus           string  `json:"lyrics_status"`
 }
 
 func (q *Queries) SearchTracks(ctx context.Context, arg SearchTracksParams) ([]SearchTracksRow, error) {
@@ -279,10 +1156,35 @@ func (q *Queries) SearchTracks(ctx context.Context, arg SearchTracksParams) ([]S
 			&i.Path,
 			&i.Title,
 			&i.Artist,
+			&i.AlbumArtist,
 			&i.Album,
 			&i.Duration,
 			&i.HasPlainLyrics,
 			&i.HasSyncedLyrics,
+			&i.LyricsOffset,
+			&i.LyricsIssue,
+			&i.TrackNumber,
+			&i.DiscNumber,
+			&i.ReleaseDate,
+			&i.Year,
+			&i.Genre,
+			&i.Composer,
+			&i.MusicbrainzRecordingID,
+			&i.MusicbrainzReleaseID,
+			&i.Isrc,
+			&i.Format,
+			&i.Bitrate,
+			&i.SampleRate,
+			&i.Channels,
+			&i.FileSize,
+			&i.AudioPath,
+			&i.CueSheetPath,
+			&i.StartOffset,
+			&i.Instrumental,
+			&i.InstrumentalSource,
+			&i.ArtistID,
+			&i.AlbumID,
+			&i.LyricsStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -302,32 +1204,200 @@ UPDATE tracks
 SET
     title = ?,
     artist = ?,
+    album_artist = ?,
     album = ?,
     duration = ?,
     has_plain_lyrics = ?,
-    has_synced_lyrics = ?
+    has_synced_lyrics = ?,
+    track_number = ?,
+    disc_number = ?,
+    release_date = ?,
+    year = ?,
+    genre = ?,
+    composer = ?,
+    musicbrainz_recording_id = ?,
+    musicbrainz_release_id = ?,
+    isrc = ?,
+    format = ?,
+    bitrate = ?,
+    sample_rate = ?,
+    channels = ?,
+    file_size = ?,
+    audio_path = ?,
+    cue_sheet_path = ?,
+    start_offset = ?
 WHERE path = ?
 `
 
 type UpdateTrackParams struct {
-	Title           sql.NullString `json:"title"`
-	Artist          sql.NullString `json:"artist"`
-	Album           sql.NullString `json:"album"`
-	Duration        float64        `json:"duration"`
-	HasPlainLyrics  bool           `json:"has_plain_lyrics"`
-	HasSyncedLyrics bool           `json:"has_synced_lyrics"`
-	Path            string         `json:"path"`
+	Title                  sql.NullString `json:"title"`
+	Artist                 sql.NullString `json:"artist"`
+	AlbumArtist            sql.NullString `json:"album_artist"`
+	Album                  sql.NullString `json:"album"`
+	Duration               float64        `json:"duration"`
+	HasPlainLyrics         bool           `json:"has_plain_lyrics"`
+	HasSyncedLyrics        bool           `json:"has_synced_lyrics"`
+	TrackNumber            sql.NullInt64  `json:"track_number"`
+	DiscNumber             sql.NullInt64  `json:"disc_number"`
+	ReleaseDate            sql.NullString `json:"release_date"`
+	Year                   sql.NullInt64  `json:"year"`
+	Genre                  sql.NullString `json:"genre"`
+	Composer               sql.NullString `json:"composer"`
+	MusicbrainzRecordingID sql.NullString `json:"musicbrainz_recording_id"`
+	MusicbrainzReleaseID   sql.NullString `json:"musicbrainz_release_id"`
+	Isrc                   sql.NullString `json:"isrc"`
+	Format                 sql.NullString `json:"format"`
+	Bitrate                sql.NullInt64  `json:"bitrate"`
+	SampleRate             sql.NullInt64  `json:"sample_rate"`
+	Channels               sql.NullInt64  `json:"channels"`
+	FileSize               sql.NullInt64  `json:"file_size"`
+	AudioPath              sql.NullString `json:"audio_path"`
+	CueSheetPath           sql.NullString `json:"cue_sheet_path"`
+	StartOffset            float64        `json:"start_offset"`
+	Path                   string         `json:"path"`
 }
 
 func (q *Queries) UpdateTrack(ctx context.Context, arg UpdateTrackParams) error {
 	_, err := q.db.ExecContext(ctx, updateTrack,
 		arg.Title,
 		arg.Artist,
+		arg.AlbumArtist,
 		arg.Album,
 		arg.Duration,
 		arg.HasPlainLyrics,
 		arg.HasSyncedLyrics,
+		arg.TrackNumber,
+		arg.DiscNumber,
+		arg.ReleaseDate,
+		arg.Year,
+		arg.Genre,
+		arg.Composer,
+		arg.MusicbrainzRecordingID,
+		arg.MusicbrainzReleaseID,
+		arg.Isrc,
+		arg.Format,
+		arg.Bitrate,
+		arg.SampleRate,
+		arg.Channels,
+		arg.FileSize,
+		arg.AudioPath,
+		arg.CueSheetPath,
+		arg.StartOffset,
 		arg.Path,
 	)
 	return err
 }
+
+const updateTrackEntities = `-- name: UpdateTrackEntities :exec
+UPDATE tracks
+SET artist_id = ?, album_id = ?
+WHERE id = ?
+`
+
+type UpdateTrackEntitiesParams struct {
+	ArtistID sql.NullInt64 `json:"artist_id"`
+	AlbumID  sql.NullInt64 `json:"album_id"`
+	ID       int64         `json:"id"`
+}
+
+func (q *Queries) UpdateTrackEntities(ctx context.Context, arg UpdateTrackEntitiesParams) error {
+	_, err := q.db.ExecContext(ctx, updateTrackEntities, arg.ArtistID, arg.AlbumID, arg.ID)
+	return err
+}
+
+const updateTrackInstrumental = `-- name: UpdateTrackInstrumental :exec
+UPDATE tracks
+SET instrumental = ?, instrumental_source = ?
+WHERE id = ?
+`
+
+type UpdateTrackInstrumentalParams struct {
+	Instrumental       bool           `json:"instrumental"`
+	InstrumentalSource sql.NullString `json:"instrumental_source"`
+	ID                 int64          `json:"id"`
+}
+
+func (q *Queries) UpdateTrackInstrumental(ctx context.Context, arg UpdateTrackInstrumentalParams) error {
+	_, err := q.db.ExecContext(ctx, updateTrackInstrumental, arg.Instrumental, arg.InstrumentalSource, arg.ID)
+	return err
+}
+
+const updateTrackInstrumentalByPath = `-- name: UpdateTrackInstrumentalByPath :exec
+UPDATE tracks
+SET instrumental = ?, instrumental_source = ?
+WHERE path = ?
+`
+
+type UpdateTrackInstrumentalByPathParams struct {
+	Instrumental       bool           `json:"instrumental"`
+	InstrumentalSource sql.NullString `json:"instrumental_source"`
+	Path               string         `json:"path"`
+}
+
+func (q *Queries) UpdateTrackInstrumentalByPath(ctx context.Context, arg UpdateTrackInstrumentalByPathParams) error {
+	_, err := q.db.ExecContext(ctx, updateTrackInstrumentalByPath, arg.Instrumental, arg.InstrumentalSource, arg.Path)
+	return err
+}
+
+const updateTrackLyricsFlags = `-- name: UpdateTrackLyricsFlags :exec
+UPDATE tracks
+SET has_plain_lyrics = ?, has_synced_lyrics = ?
+WHERE id = ?
+`
+
+type UpdateTrackLyricsFlagsParams struct {
+	HasPlainLyrics  bool  `json:"has_plain_lyrics"`
+	HasSyncedLyrics bool  `json:"has_synced_lyrics"`
+	ID              int64 `json:"id"`
+}
+
+func (q *Queries) UpdateTrackLyricsFlags(ctx context.Context, arg UpdateTrackLyricsFlagsParams) error {
+	_, err := q.db.ExecContext(ctx, updateTrackLyricsFlags, arg.HasPlainLyrics, arg.HasSyncedLyrics, arg.ID)
+	return err
+}
+
+const updateTrackLyricsIssue = `-- name: UpdateTrackLyricsIssue :exec
+UPDATE tracks
+SET lyrics_issue = ?
+WHERE path = ?
+`
+
+type UpdateTrackLyricsIssueParams struct {
+	LyricsIssue sql.NullString `json:"lyrics_issue"`
+	Path        string         `json:"path"`
+}
+
+func (q *Queries) UpdateTrackLyricsIssue(ctx context.Context, arg UpdateTrackLyricsIssueParams) error {
+	_, err := q.db.ExecContext(ctx, updateTrackLyricsIssue, arg.LyricsIssue, arg.Path)
+	return err
+}
+
+const updateTrackLyricsOffset = `-- name: UpdateTrackLyricsOffset :exec
+UPDATE tracks
+SET lyrics_offset = ?
+WHERE id = ?
+`
+
+type UpdateTrackLyricsOffsetParams struct {
+	LyricsOffset int64 `json:"lyrics_offset"`
+	ID           int64 `json:"id"`
+}
+
+func (q *Queries) UpdateTrackLyricsOffset(ctx context.Context, arg UpdateTrackLyricsOffsetParams) error {
+	_, err := q.db.ExecContext(ctx, updateTrackLyricsOffset, arg.LyricsOffset, arg.ID)
+	return err
+}
+
+const updateTrackLyricsStatus = `-- name: UpdateTrackLyricsStatus :exec
+UPDATE tracks SET lyrics_status = ? WHERE id = ?
+`
+
+type UpdateTrackLyricsStatusParams struct {
+	LyricsStatus string `json:"lyrics_status"`
+	ID           int64  `json:"id"`
+}
+
+func (q *Queries) UpdateTrackLyricsStatus(ctx context.Context, arg UpdateTrackLyricsStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateTrackLyricsStatus, arg.LyricsStatus, arg.ID)
+	return err
+}
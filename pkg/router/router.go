@@ -29,10 +29,24 @@ func BuildRouter(c *services.Container) error {
 	c.Web.Use(recover.New())
 
 	// API Routers
+	if c.Config.Database.Backup.Download {
+		c.Web.Get("/api/backup", controllers.NewBackupController(c).Download)
+	}
 	c.Web.Get("/api/stats", controllers.NewSongsStatController(c).Index)
+	c.Web.Get("/api/stats/history", controllers.NewSongsStatController(c).History)
 	c.Web.Get("/api/tracks", controllers.NewSongsController(c).Index)
 	c.Web.Get("/api/tracks/:id", controllers.NewSongsController(c).Show)
+	c.Web.Get("/api/tracks/:id/lyrics", controllers.NewSongsController(c).Lyrics)
+	c.Web.Get("/api/tracks/:id/attempts", controllers.NewSongsController(c).Attempts)
+	c.Web.Get("/api/tracks/:id/cover", controllers.NewSongsController(c).Cover)
+	c.Web.Post("/api/tracks/:id/offset", controllers.NewSongsController(c).Offset)
+	c.Web.Put("/api/tracks/:id/instrumental", controllers.NewSongsController(c).Instrumental)
+	c.Web.Put("/api/tracks/:id/status", controllers.NewSongsController(c).Status)
+	c.Web.Get("/api/artists", controllers.NewArtistsController(c).Index)
+	c.Web.Get("/api/albums", controllers.NewAlbumsController(c).Index)
+	c.Web.Get("/api/albums/:id/tracks", controllers.NewAlbumsController(c).Tracks)
 	c.Web.Get("/api/search/tracks", controllers.NewSongsController(c).Search)
+	c.Web.Get("/api/search/lyrics", controllers.NewSongsController(c).SearchLyrics)
 
 	return nil
 }
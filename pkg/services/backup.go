@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/backup"
+)
+
+// BackupService backs the database up at a regular interval and removes the oldest backups.
+type BackupService struct {
+	db        *sql.DB
+	directory string
+	interval  time.Duration
+	retention int
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewBackupService creates a BackupService writing to directory every interval and keeping the
+// given number of backups.
+func NewBackupService(db *sql.DB, directory string, interval time.Duration, retention int) *BackupService {
+	return &BackupService{
+		db:        db,
+		directory: directory,
+		interval:  interval,
+		retention: retention,
+		done:      make(chan struct{}),
+	}
+}
+
+// Start begins backing up the database, the first backup being taken after one interval.
+func (bs *BackupService) Start(ctx context.Context) {
+	bs.wg.Add(1)
+	go bs.schedule(ctx)
+	slog.Info("database backups scheduled", "directory", bs.directory, "interval", bs.interval)
+}
+
+// schedule is the loop taking the backups.
+func (bs *BackupService) schedule(ctx context.Context) {
+	defer bs.wg.Done()
+
+	ticker := time.NewTicker(bs.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := bs.Run(ctx); err != nil {
+				slog.Error("failed to back up the database", "error", err)
+			}
+
+		case <-ctx.Done():
+			return
+
+		case <-bs.done:
+			return
+		}
+	}
+}
+
+// Run backs the database up now, removes the backups beyond the retention and returns the path of
+// the new backup.
+func (bs *BackupService) Run(ctx context.Context) (string, error) {
+	p := filepath.Join(bs.directory, backup.FileName(time.Now()))
+	if err := backup.Backup(ctx, bs.db, p); err != nil {
+		return "", err
+	}
+	slog.Info("backed up the database", "path", p)
+
+	removed, err := backup.Prune(bs.directory, bs.retention)
+	for _, r := range removed {
+		slog.Info("removed old database backup", "path", r)
+	}
+	return p, err
+}
+
+// Stop stops the scheduled backups and waits for a running backup to finish.
+func (bs *BackupService) Stop() {
+	close(bs.done)
+	bs.wg.Wait()
+}
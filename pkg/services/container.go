@@ -9,7 +9,10 @@ import (
 	"strings"
 
 	"github.com/gerald-lbn/refrain/config"
+	"github.com/gerald-lbn/refrain/db"
 	"github.com/gerald-lbn/refrain/pkg/log"
+	"github.com/gerald-lbn/refrain/pkg/migrate"
+	"github.com/gerald-lbn/refrain/pkg/music"
 	"github.com/gerald-lbn/refrain/pkg/music/lrclib"
 	"github.com/gofiber/fiber/v2"
 	_ "github.com/mattn/go-sqlite3"
@@ -25,6 +28,15 @@ type Container struct {
 	// Database stores the connection to the database.
 	Database *sql.DB
 
+	// Migrator applies the embedded migrations to the database.
+	Migrator *migrate.Migrator
+
+	// Backups backs the database up on a schedule.
+	Backups *BackupService
+
+	// Stats records the history of the lyrics coverage.
+	Stats *StatsService
+
 	// Tasks stores the task client.
 	Tasks *backlite.Client
 
@@ -35,6 +47,18 @@ type Container struct {
 	Web *fiber.App
 
 	LyricsProvider *lrclib.LRCLibProvider
+
+	// LyricsPaths resolves the paths of the lyrics files of the audio files.
+	LyricsPaths music.LyricsPathTemplate
+
+	// MetadataOptions stores the options used to extract the metadata of audio files.
+	MetadataOptions []music.ExtractOption
+
+	// Covers caches the thumbnails of cover art.
+	Covers *music.CoverCache
+
+	// Normalizer simplifies tags to search lyrics, nil when normalization is disabled.
+	Normalizer *music.Normalizer
 }
 
 // NewContainer creates and initializes a new Container.
@@ -43,12 +67,27 @@ func NewContainer() *Container {
 	c.initConfig()
 	c.initWeb()
 	c.initDatabase()
+	c.initBackups()
+	c.initStats()
 	c.initLyricsProvider()
+	c.initMetadata()
 	c.initTasks()
 	c.initWatcher()
 	return c
 }
 
+// NewCommandContainer creates a Container for the command-line operations, holding the configuration,
+// the database and the services built on them. Unlike NewContainer, it neither watches the libraries
+// nor creates the task client.
+func NewCommandContainer() *Container {
+	c := new(Container)
+	c.initConfig()
+	c.initDatabase()
+	c.initBackups()
+	c.initMetadata()
+	return c
+}
+
 // Shutdown gracefully shuts the Container down
 func (c *Container) Shutdown() error {
 	if c.Watcher != nil {
@@ -57,10 +96,20 @@ func (c *Container) Shutdown() error {
 		}
 	}
 
+	if c.Backups != nil {
+		c.Backups.Stop()
+	}
+
+	if c.Stats != nil {
+		c.Stats.Stop()
+	}
+
 	// Shutdown the task runner.
-	taskCtx, taskCancel := context.WithTimeout(context.Background(), c.Config.Tasks.ShutdownTimeout)
-	defer taskCancel()
-	c.Tasks.Stop(taskCtx)
+	if c.Tasks != nil {
+		taskCtx, taskCancel := context.WithTimeout(context.Background(), c.Config.Tasks.ShutdownTimeout)
+		defer taskCancel()
+		c.Tasks.Stop(taskCtx)
+	}
 
 	// Shutdown the database.
 	if err := c.Database.Close(); err != nil {
@@ -102,6 +151,28 @@ func (c *Container) initDatabase() {
 	if err != nil {
 		panic(err)
 	}
+
+	migrations, err := migrate.Load(db.Migrations, "migrations")
+	if err != nil {
+		panic(fmt.Sprintf("failed to load migrations: %v", err))
+	}
+	c.Migrator = migrate.New(c.Database, migrations)
+
+	// Running against a schema this version does not know about could corrupt the data
+	if err := c.Migrator.Check(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+// initBackups initializes the database backup service, started by the application when enabled.
+func (c *Container) initBackups() {
+	cfg := c.Config.Database.Backup
+	c.Backups = NewBackupService(c.Database, cfg.Directory, cfg.Interval, cfg.Retention)
+}
+
+// initStats initializes the coverage snapshot service, started by the application when enabled.
+func (c *Container) initStats() {
+	c.Stats = NewStatsService(c.Database, c.Config.Libraries.Paths, c.Config.Stats.SnapshotInterval)
 }
 
 // initLyrics providers initializes the lyrics provider
@@ -109,6 +180,43 @@ func (c *Container) initLyricsProvider() {
 	c.LyricsProvider = lrclib.NewLRCLibProvider()
 }
 
+// initMetadata initializes the options used to extract metadata.
+func (c *Container) initMetadata() {
+	patterns, err := music.NewPathPatterns(c.Config.Metadata.PathPatterns)
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse metadata path patterns: %v", err))
+	}
+
+	c.LyricsPaths = music.LyricsPathTemplate{
+		Template:    c.Config.Lyrics.PathTemplate,
+		CueTemplate: c.Config.Lyrics.CuePathTemplate,
+		Root:        c.Config.Lyrics.Root,
+		Libraries:   c.Config.Libraries.Paths,
+	}
+
+	c.MetadataOptions = []music.ExtractOption{
+		music.WithLyricsPathTemplate(c.LyricsPaths),
+		music.WithPathPatterns(c.Config.Libraries.Paths, patterns...),
+	}
+
+	if normalization := c.Config.Lyrics.Normalization; normalization.Enabled {
+		c.Normalizer = music.NewNormalizer(music.NormalizationRules{
+			FoldUnicode:       normalization.FoldUnicode,
+			StripBrackets:     normalization.StripBrackets,
+			StripSuffixes:     normalization.StripSuffixes,
+			ExtractFeaturing:  normalization.ExtractFeaturing,
+			AmpersandVariants: normalization.AmpersandVariants,
+			Keywords:          normalization.Keywords,
+			MaxQueries:        normalization.MaxQueries,
+		})
+	}
+
+	c.Covers = &music.CoverCache{
+		Directory: c.Config.Covers.CacheDirectory,
+		Size:      c.Config.Covers.Size,
+	}
+}
+
 func (c *Container) initTasks() {
 	var err error
 	// You could use a separate database for tasks, if you'd like, but using one
@@ -166,7 +274,25 @@ func openDB(driver, connection string) (*sql.DB, error) {
 				return nil, err
 			}
 		}
+
+		connection = withImmediateTransactions(connection)
 	}
 
 	return sql.Open(driver, connection)
 }
+
+// withImmediateTransactions makes the transactions of the connection take the write lock when they
+// begin, unless the connection string already sets their locking mode. A deferred transaction reading
+// before it writes, such as persisting a track, fails with SQLITE_BUSY as soon as another one wrote
+// meanwhile, without waiting for the busy timeout.
+func withImmediateTransactions(connection string) string {
+	if strings.Contains(connection, "_txlock=") {
+		return connection
+	}
+
+	separator := "?"
+	if strings.Contains(connection, "?") {
+		separator = "&"
+	}
+	return connection + separator + "_txlock=immediate"
+}
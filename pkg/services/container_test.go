@@ -15,9 +15,25 @@ var _ = Describe("Container", func() {
 			Expect(c.Config).ToNot(BeNil())
 			Expect(c.Watcher).ToNot(BeNil())
 			Expect(c.LyricsProvider).ToNot(BeNil())
+			Expect(c.MetadataOptions).ToNot(BeEmpty())
 			Expect(c.Database).ToNot(BeNil())
 			Expect(c.Tasks).ToNot(BeNil())
 			Expect(c.Web).ToNot(BeNil())
 		})
 	})
+
+	When("creating a container for a command", func() {
+		It("should only initialize the configuration and the database", func() {
+			c := services.NewCommandContainer()
+			DeferCleanup(c.Shutdown)
+
+			Expect(c.Config).ToNot(BeNil())
+			Expect(c.Database).ToNot(BeNil())
+			Expect(c.Migrator).ToNot(BeNil())
+			Expect(c.MetadataOptions).ToNot(BeEmpty())
+			Expect(c.Watcher).To(BeNil())
+			Expect(c.Tasks).To(BeNil())
+			Expect(c.Web).To(BeNil())
+		})
+	})
 })
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/library"
+)
+
+// StatsService records snapshots of the lyrics coverage of the libraries at a regular interval.
+type StatsService struct {
+	db        *sql.DB
+	libraries []string
+	interval  time.Duration
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewStatsService creates a StatsService recording the coverage of libraries every interval.
+func NewStatsService(db *sql.DB, libraries []string, interval time.Duration) *StatsService {
+	return &StatsService{
+		db:        db,
+		libraries: libraries,
+		interval:  interval,
+		done:      make(chan struct{}),
+	}
+}
+
+// Start records a snapshot now then every interval.
+func (ss *StatsService) Start(ctx context.Context) {
+	ss.wg.Add(1)
+	go ss.schedule(ctx)
+	slog.Info("coverage snapshots scheduled", "interval", ss.interval)
+}
+
+// schedule is the loop recording the snapshots.
+func (ss *StatsService) schedule(ctx context.Context) {
+	defer ss.wg.Done()
+
+	ss.record(ctx)
+
+	ticker := time.NewTicker(ss.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ss.record(ctx)
+
+		case <-ctx.Done():
+			return
+
+		case <-ss.done:
+			return
+		}
+	}
+}
+
+// record records the snapshots of today.
+func (ss *StatsService) record(ctx context.Context) {
+	if err := library.RecordSnapshots(ctx, ss.db, ss.libraries, time.Now()); err != nil {
+		slog.Error("failed to record coverage snapshots", "error", err)
+	}
+}
+
+// Stop stops the snapshots and waits for a running one to finish.
+func (ss *StatsService) Stop() {
+	close(ss.done)
+	ss.wg.Wait()
+}
@@ -2,14 +2,21 @@ package tasks
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/gerald-lbn/refrain/pkg/log"
 	"github.com/gerald-lbn/refrain/pkg/music"
+	"github.com/gerald-lbn/refrain/pkg/music/lrc"
 	"github.com/gerald-lbn/refrain/pkg/music/lrclib"
+	"github.com/gerald-lbn/refrain/pkg/repository"
 	"github.com/gerald-lbn/refrain/pkg/services"
+	dbUtils "github.com/gerald-lbn/refrain/pkg/utils/db"
 	"github.com/gerald-lbn/refrain/pkg/utils/file"
 	"github.com/mikestefanello/backlite"
 )
@@ -34,69 +41,179 @@ func (t DownloadLyricsTask) Config() backlite.QueueConfig {
 
 func NewDownloadLyricsTaskQueue(c *services.Container) backlite.Queue {
 	return backlite.NewQueue(func(ctx context.Context, dlt DownloadLyricsTask) error {
-		if isAudio, err := file.IsAudioFile(dlt.Path); err != nil {
+		if isAudio, err := file.IsAudioFile(music.AudioFilePath(dlt.Path)); err != nil {
 			return err
 		} else if !isAudio {
 			return nil
 		}
 
-		track, err := music.ExtractMetadata(dlt.Path)
+		// Lyrics of single-file albums are downloaded for the tracks of their CUE sheet
+		if music.HasCueSheet(dlt.Path) {
+			return nil
+		}
+
+		track, err := extractMetadata(c, dlt.Path)
 		if err != nil {
 			return err
 		}
 
-		// Skip task if track already has both lyrics
+		repo := repository.New(c.Database)
+
+		// Skip tracks known to be instrumental, and those the user doesn't want lyrics for.
+		// The user's decision on a track prevails over its tags. Tracks not persisted yet have a
+		// zero record.
+		record, err := repo.GetTrackByPath(ctx, track.Path)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			if track.Instrumental {
+				return nil
+			}
+		case err != nil:
+			return err
+		case record.Instrumental, record.LyricsStatus == string(music.LyricsStatusIgnored):
+			return nil
+		case track.Instrumental && record.InstrumentalSource != string(music.InstrumentalSourceManual):
+			return nil
+		}
+
+		// Extract lyrics embedded in the audio tags to sidecar files
+		if c.Config.Lyrics.ExtractEmbedded {
+			if track.EmbeddedPlainLyrics != "" && !track.HasPlainLyrics {
+				if err := writeLyricsFile(track.PlainLyricsPath, track.EmbeddedPlainLyrics); err != nil {
+					return err
+				}
+				track.HasPlainLyrics = true
+			}
+
+			if track.EmbeddedSyncedLyrics != "" && !track.HasSyncedLyrics {
+				if err := writeLyricsFile(track.SyncedLyricsPath, track.EmbeddedSyncedLyrics); err != nil {
+					return err
+				}
+				track.HasSyncedLyrics = true
+			}
+		}
+
+		// Tracks of a CUE sheet share their audio file, so their lyrics can't be embedded.
+		needsEmbedding := c.Config.Lyrics.Embed.Enabled && !track.HasEmbeddedLyrics() && !track.IsVirtual()
+
+		// Skip the provider when the track already has both lyrics, embedding them when wanted
 		if track.HasBothLyricsStoredLocally() {
+			if needsEmbedding {
+				return embedLyrics(c, track)
+			}
 			return nil
 		}
 
-		var options lrclib.SearchLyricsOptions
-		if track.HasAllMetadata() {
-			options = lrclib.WithTrackArtistAndAlbumName(*track.Title, *track.Artist, *track.Album)
-		} else if track.Artist == nil || track.Title == nil {
+		attempts := newLyricsAttempts(repo, record.ID, track)
+
+		queries := track.SearchQueries(c.Normalizer)
+		if len(queries) == 0 {
 			log.Default().Warn("skipping track",
 				slog.String("path", dlt.Path),
 				slog.String("reason", "not enough metadata to search"),
 			)
+			attempts.record(ctx, music.SearchQuery{}, music.LyricsAttemptError, 0, lrclib.ErrMissingTrackOrArtistName.Error())
+			if err := setLyricsStatus(ctx, repo, attempts.trackID, music.LyricsStatusError); err != nil {
+				return err
+			}
 			return lrclib.ErrMissingTrackOrArtistName
-		} else {
-			options = lrclib.WithTrackAndArtistName(*track.Title, *track.Artist)
 		}
 
-		lyrics, err := c.LyricsProvider.GetLyrics(ctx, options, int(track.Duration))
-		if err != nil {
+		if err := setLyricsStatus(ctx, repo, attempts.trackID, music.LyricsStatusSearching); err != nil {
 			return err
 		}
 
-		// Skip instrumental track
+		lyrics, query, err := getLyrics(ctx, c, queries, attempts)
+		if errors.Is(err, lrclib.ErrLyricsNotFound) {
+			// The outcome is final, retrying would only ask the provider again
+			return setLyricsStatus(ctx, repo, attempts.trackID, searchedLyricsStatus(track))
+		}
+		if err != nil {
+			return failLyricsSearch(ctx, repo, attempts.trackID, err)
+		}
+
+		// Remember instrumental tracks so they are not looked up again, unless the user said otherwise
 		if lyrics.Instrumental {
-			return nil
+			attempts.record(ctx, query, music.LyricsAttemptHit, lyrics.ID, "")
+
+			if record.InstrumentalSource == string(music.InstrumentalSourceManual) {
+				return setLyricsStatus(ctx, repo, record.ID, searchedLyricsStatus(track))
+			}
+
+			if err := repo.UpdateTrackInstrumentalByPath(ctx, repository.UpdateTrackInstrumentalByPathParams{
+				Instrumental:       true,
+				InstrumentalSource: dbUtils.StringToNullString(string(music.InstrumentalSourceProvider)),
+				Path:               track.Path,
+			}); err != nil {
+				return failLyricsSearch(ctx, repo, record.ID, err)
+			}
+			return setLyricsStatus(ctx, repo, record.ID, music.LyricsStatusInstrumental)
 		}
 
-		// Write plain lyrics
-		if len(lyrics.PlainLyrics) > 0 && !track.HasPlainLyrics {
-			err = os.WriteFile(track.PlainLyricsPath, []byte(lyrics.PlainLyrics), 0644)
-			if err != nil {
-				log.Default().Error("failed to write file",
-					slog.String("path", track.PlainLyricsPath),
-					slog.String("content", lyrics.PlainLyrics),
-					slog.String("error", err.Error()),
+		// Check the lyrics belong to the track before writing them
+		if validation := c.Config.Lyrics.Validation; validation.Enabled {
+			issue := strings.Join(validateLyrics(track, lyrics, validation.DurationTolerance), "; ")
+			if err := repo.UpdateTrackLyricsIssue(ctx, repository.UpdateTrackLyricsIssueParams{
+				LyricsIssue: dbUtils.StringToNullString(issue),
+				Path:        track.Path,
+			}); err != nil {
+				return failLyricsSearch(ctx, repo, attempts.trackID, err)
+			}
+
+			if issue != "" {
+				log.Default().Warn("suspicious lyrics",
+					slog.String("path", track.Path),
+					slog.Int("lyrics_id", lyrics.ID),
+					slog.String("issue", issue),
+					slog.Bool("rejected", validation.Reject),
 				)
 
-				return err
+				if validation.Reject {
+					attempts.record(ctx, query, music.LyricsAttemptRejected, lyrics.ID, issue)
+					return setLyricsStatus(ctx, repo, attempts.trackID, searchedLyricsStatus(track))
+				}
+			}
+		}
+		attempts.record(ctx, query, music.LyricsAttemptHit, lyrics.ID, "")
+
+		// Apply the offset recorded for the track so a re-download keeps the user's adjustment
+		if record.LyricsOffset != 0 {
+			lyrics.SyncedLyrics = lrc.Shift(lyrics.SyncedLyrics, time.Duration(record.LyricsOffset)*time.Millisecond)
+		}
+
+		// Write plain lyrics
+		written := false
+		if len(lyrics.PlainLyrics) > 0 && !track.HasPlainLyrics {
+			if err := writeLyricsFile(track.PlainLyricsPath, lyrics.PlainLyrics); err != nil {
+				return failLyricsSearch(ctx, repo, attempts.trackID, err)
 			}
+			track.HasPlainLyrics = true
+			written = true
 		}
 
 		// Write synced lyrics
 		if len(lyrics.SyncedLyrics) > 0 && !track.HasSyncedLyrics {
-			err = os.WriteFile(track.SyncedLyricsPath, []byte(lyrics.SyncedLyrics), 0644)
-			if err != nil {
-				log.Default().Error("failed to write file",
-					slog.String("path", track.SyncedLyricsPath),
-					slog.String("content", lyrics.SyncedLyrics),
-					slog.String("error", err.Error()),
-				)
+			if err := writeLyricsFile(track.SyncedLyricsPath, lyrics.SyncedLyrics); err != nil {
+				return failLyricsSearch(ctx, repo, attempts.trackID, err)
+			}
+			track.HasSyncedLyrics = true
+			written = true
+		}
+
+		// Remember where the written lyrics come from
+		if written {
+			if err := storeDownloadedLyrics(ctx, repo, record.ID, track, lyrics); err != nil {
+				return failLyricsSearch(ctx, repo, attempts.trackID, err)
+			}
+		}
+
+		if err := setLyricsStatus(ctx, repo, attempts.trackID, searchedLyricsStatus(track)); err != nil {
+			return err
+		}
 
+		// Embed lyrics into the audio tags
+		if needsEmbedding {
+			if err := embedLyrics(c, track); err != nil {
 				return err
 			}
 		}
@@ -104,3 +221,196 @@ func NewDownloadLyricsTaskQueue(c *services.Container) backlite.Queue {
 		return nil
 	})
 }
+
+// embedLyrics embeds the lyrics files of the track into its audio tags, or only logs the tags it
+// would write in dry run mode.
+func embedLyrics(c *services.Container, track *music.Metadata) error {
+	plain, synced, _, err := track.ReadLyrics()
+	if err != nil {
+		return err
+	}
+
+	embedCfg := c.Config.Lyrics.Embed
+	tags, err := music.EmbedLyrics(track.Path, plain, synced, music.EmbedLyricsOptions{
+		DryRun:          embedCfg.DryRun,
+		BackupDirectory: embedCfg.BackupDirectory,
+	})
+	if err != nil {
+		log.Default().Error("failed to embed lyrics",
+			slog.String("path", track.Path),
+			slog.String("error", err.Error()),
+		)
+
+		return err
+	}
+
+	if embedCfg.DryRun {
+		for tag := range tags {
+			log.Default().Info("dry run: would embed lyrics",
+				slog.String("path", track.Path),
+				slog.String("tag", tag),
+			)
+		}
+	}
+	return nil
+}
+
+// storeDownloadedLyrics records the lyrics files of the track along with the provider they were
+// downloaded from. Tracks not persisted yet, with a zero ID, get their lyrics stored once they are.
+func storeDownloadedLyrics(ctx context.Context, repo *repository.Queries, trackID int64, track *music.Metadata, lyrics *lrclib.Lyrics) error {
+	if trackID == 0 {
+		return nil
+	}
+
+	if err := repo.UpdateTrackLyricsFlags(ctx, repository.UpdateTrackLyricsFlagsParams{
+		HasPlainLyrics:  track.HasPlainLyrics,
+		HasSyncedLyrics: track.HasSyncedLyrics,
+		ID:              trackID,
+	}); err != nil {
+		return err
+	}
+
+	plain, synced, _, err := track.ReadLyrics()
+	if err != nil {
+		return err
+	}
+
+	return repo.UpsertLyrics(ctx, repository.UpsertLyricsParams{
+		TrackID:      trackID,
+		PlainLyrics:  dbUtils.StringToNullString(plain),
+		SyncedLyrics: dbUtils.StringToNullString(synced),
+		Provider:     string(music.LyricsProviderLRCLib),
+		ProviderID:   dbUtils.IntToNullInt64(lyrics.ID),
+		FetchedAt:    time.Now(),
+		ContentHash:  music.LyricsHash(plain, synced),
+	})
+}
+
+// failLyricsSearch marks the lyrics of the track as failed, so a task failing once the search has
+// started does not leave the track searching, and returns err.
+func failLyricsSearch(ctx context.Context, repo *repository.Queries, trackID int64, err error) error {
+	if statusErr := setLyricsStatus(ctx, repo, trackID, music.LyricsStatusError); statusErr != nil {
+		return errors.Join(err, statusErr)
+	}
+	return err
+}
+
+// searchedLyricsStatus returns the status of a track once its lyrics were searched, from the lyrics
+// it has.
+func searchedLyricsStatus(track *music.Metadata) music.LyricsStatus {
+	status := music.LyricsStatusOf(track.HasPlainLyrics, track.HasSyncedLyrics)
+	if status == music.LyricsStatusPending {
+		return music.LyricsStatusNotFound
+	}
+	return status
+}
+
+// getLyrics tries each query in order and returns the lyrics of the first one known to the provider,
+// along with that query. Queries unknown to the provider and provider failures are recorded as attempts.
+func getLyrics(ctx context.Context, c *services.Container, queries []music.SearchQuery, attempts lyricsAttempts) (*lrclib.Lyrics, music.SearchQuery, error) {
+	var err error
+	for _, query := range queries {
+		var options lrclib.SearchLyricsOptions
+		if query.AlbumName != "" {
+			options = lrclib.WithTrackArtistAndAlbumName(query.TrackName, query.ArtistName, query.AlbumName)
+		} else {
+			options = lrclib.WithTrackAndArtistName(query.TrackName, query.ArtistName)
+		}
+
+		var lyrics *lrclib.Lyrics
+		lyrics, err = c.LyricsProvider.GetLyrics(ctx, options, attempts.duration)
+		if err == nil {
+			return lyrics, query, nil
+		}
+		if !errors.Is(err, lrclib.ErrLyricsNotFound) {
+			attempts.record(ctx, query, music.LyricsAttemptError, 0, err.Error())
+			return nil, query, err
+		}
+		attempts.record(ctx, query, music.LyricsAttemptMiss, 0, "")
+	}
+	return nil, music.SearchQuery{}, err
+}
+
+// lyricsAttempts records the attempts to fetch the lyrics of a track.
+type lyricsAttempts struct {
+	repo *repository.Queries
+	// trackID is zero for tracks not persisted yet, whose attempts are not recorded
+	trackID  int64
+	duration int
+}
+
+// newLyricsAttempts records the attempts to fetch the lyrics of the track with the given ID, zero
+// when it is not persisted yet.
+func newLyricsAttempts(repo *repository.Queries, trackID int64, track *music.Metadata) lyricsAttempts {
+	return lyricsAttempts{repo: repo, trackID: trackID, duration: int(track.Duration)}
+}
+
+// record records an attempt made with the given query. A failure to record it is only logged, as the
+// history must not prevent lyrics from being fetched.
+func (a lyricsAttempts) record(ctx context.Context, query music.SearchQuery, outcome music.LyricsAttemptOutcome, lyricsID int, message string) {
+	if a.trackID == 0 {
+		return
+	}
+
+	err := a.repo.CreateLyricsAttempt(ctx, repository.CreateLyricsAttemptParams{
+		TrackID:     a.trackID,
+		AttemptedAt: time.Now(),
+		Provider:    string(music.LyricsProviderLRCLib),
+		TrackName:   dbUtils.StringToNullString(query.TrackName),
+		ArtistName:  dbUtils.StringToNullString(query.ArtistName),
+		AlbumName:   dbUtils.StringToNullString(query.AlbumName),
+		Duration:    dbUtils.IntToNullInt64(a.duration),
+		Outcome:     string(outcome),
+		Error:       dbUtils.StringToNullString(message),
+		ProviderID:  dbUtils.IntToNullInt64(lyricsID),
+	})
+	if err != nil {
+		log.Default().Error("failed to record lyrics attempt",
+			slog.Int64("track_id", a.trackID),
+			slog.String("outcome", string(outcome)),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// validateLyrics lists the issues making the lyrics unlikely to belong to the track.
+func validateLyrics(track *music.Metadata, lyrics *lrclib.Lyrics, tolerance time.Duration) []string {
+	issues := track.Mismatches(music.Candidate{
+		TrackName:  lyrics.TrackName,
+		ArtistName: lyrics.ArtistName,
+		AlbumName:  lyrics.AlbumName,
+		Duration:   lyrics.Duration,
+	}, tolerance)
+
+	if lyrics.SyncedLyrics != "" {
+		duration := time.Duration(track.Duration * float64(time.Second))
+		if err := lrc.Validate(lyrics.SyncedLyrics, duration, tolerance); err != nil {
+			issues = append(issues, err.Error())
+		}
+	}
+
+	return issues
+}
+
+// writeLyricsFile writes lyrics to the given path, creating its directory if needed and logging any failure.
+func writeLyricsFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Default().Error("failed to create lyrics directory",
+			slog.String("path", path),
+			slog.String("error", err.Error()),
+		)
+
+		return err
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		log.Default().Error("failed to write file",
+			slog.String("path", path),
+			slog.String("content", content),
+			slog.String("error", err.Error()),
+		)
+
+		return err
+	}
+	return nil
+}
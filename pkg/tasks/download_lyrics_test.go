@@ -0,0 +1,248 @@
+package tasks_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	"github.com/gerald-lbn/refrain/pkg/music/lrclib"
+	"github.com/gerald-lbn/refrain/pkg/repository"
+	"github.com/gerald-lbn/refrain/pkg/services"
+	"github.com/gerald-lbn/refrain/pkg/tasks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DownloadLyricsTask", func() {
+	var (
+		ctx       context.Context
+		c         *services.Container
+		repo      *repository.Queries
+		audioPath string
+		requests  int
+		// respond answers the requests made to the provider
+		respond func(w http.ResponseWriter, r *http.Request)
+	)
+
+	// lyricsFor answers with lyrics matching the requested track, changed by edit.
+	lyricsFor := func(edit func(*lrclib.Lyrics)) func(http.ResponseWriter, *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			duration, _ := strconv.ParseFloat(r.URL.Query().Get("duration"), 64)
+			lyrics := lrclib.Lyrics{
+				ID:           42,
+				TrackName:    r.URL.Query().Get("track_name"),
+				ArtistName:   r.URL.Query().Get("artist_name"),
+				AlbumName:    r.URL.Query().Get("album_name"),
+				Duration:     duration,
+				PlainLyrics:  "You have become the voice in my head",
+				SyncedLyrics: "[00:01.00] You have become the voice in my head",
+			}
+			if edit != nil {
+				edit(&lyrics)
+			}
+			Expect(json.NewEncoder(w).Encode(lyrics)).To(Succeed())
+		}
+	}
+
+	persist := func() error {
+		return process(ctx, tasks.NewPersistTrackInfoQueue(c), tasks.PersistTrackInfoTask{Path: audioPath})
+	}
+
+	download := func() error {
+		return process(ctx, tasks.NewDownloadLyricsTaskQueue(c), tasks.DownloadLyricsTask{Path: audioPath})
+	}
+
+	track := func() repository.GetTrackByPathRow {
+		record, err := repo.GetTrackByPath(ctx, audioPath)
+		Expect(err).ToNot(HaveOccurred())
+		return record
+	}
+
+	attempts := func() []repository.GetLyricsAttemptsByTrackIDRow {
+		rows, err := repo.GetLyricsAttemptsByTrackID(ctx, track().ID)
+		Expect(err).ToNot(HaveOccurred())
+		return rows
+	}
+
+	outcomes := func() []string {
+		o := []string{}
+		for _, attempt := range attempts() {
+			o = append(o, attempt.Outcome)
+		}
+		return o
+	}
+
+	lyricsPath := func(ext string) string {
+		return filepath.Join(filepath.Dir(audioPath), "Vore."+ext)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c = newContainer(ctx)
+		repo = repository.New(c.Database)
+		c.Covers = &music.CoverCache{Directory: GinkgoT().TempDir(), Size: 100}
+
+		audioPath = copyAudio()
+
+		requests = 0
+		respond = lyricsFor(nil)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			respond(w, r)
+		}))
+		DeferCleanup(server.Close)
+
+		c.LyricsProvider = lrclib.NewLRCLibProvider()
+		c.LyricsProvider.BaseURL = server.URL
+
+		Expect(persist()).To(Succeed())
+		Expect(track().LyricsStatus).To(Equal(string(music.LyricsStatusPending)))
+	})
+
+	When("the provider has lyrics for the track", func() {
+		It("should write them and record the hit", func() {
+			Expect(download()).To(Succeed())
+
+			Expect(lyricsPath("lrc")).To(BeARegularFile())
+			Expect(lyricsPath("txt")).To(BeARegularFile())
+			Expect(track().LyricsStatus).To(Equal(string(music.LyricsStatusFound)))
+			Expect(track().HasSyncedLyrics).To(BeTrue())
+
+			stored, err := repo.GetLyricsByTrackID(ctx, track().ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.ProviderID).To(BeEquivalentTo(42))
+
+			Expect(attempts()).To(HaveLen(1))
+			Expect(attempts()[0].Outcome).To(Equal(string(music.LyricsAttemptHit)))
+			Expect(attempts()[0].TrackName).To(Equal("Vore"))
+			Expect(attempts()[0].ArtistName).To(Equal("Sleep Token"))
+			Expect(attempts()[0].ProviderID).To(BeEquivalentTo(42))
+		})
+
+		It("should only provide the missing lyrics", func() {
+			respond = lyricsFor(func(l *lrclib.Lyrics) { l.SyncedLyrics = "" })
+			Expect(download()).To(Succeed())
+
+			Expect(lyricsPath("txt")).To(BeARegularFile())
+			Expect(lyricsPath("lrc")).ToNot(BeAnExistingFile())
+			Expect(track().LyricsStatus).To(Equal(string(music.LyricsStatusPartial)))
+		})
+
+		It("should not search the lyrics of ignored tracks", func() {
+			Expect(repo.UpdateTrackLyricsStatus(ctx, repository.UpdateTrackLyricsStatusParams{
+				LyricsStatus: string(music.LyricsStatusIgnored),
+				ID:           track().ID,
+			})).To(Succeed())
+
+			Expect(download()).To(Succeed())
+			Expect(requests).To(BeZero())
+			Expect(track().LyricsStatus).To(Equal(string(music.LyricsStatusIgnored)))
+		})
+	})
+
+	When("the provider has no lyrics for the track", func() {
+		BeforeEach(func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+
+		It("should complete the task without retrying it", func() {
+			Expect(download()).To(Succeed())
+			Expect(track().LyricsStatus).To(Equal(string(music.LyricsStatusNotFound)))
+			Expect(lyricsPath("lrc")).ToNot(BeAnExistingFile())
+		})
+
+		It("should record a miss for every query", func() {
+			Expect(download()).To(Succeed())
+			Expect(requests).To(BeNumerically(">", 0))
+			Expect(outcomes()).To(HaveLen(requests))
+			Expect(outcomes()).To(HaveEach(string(music.LyricsAttemptMiss)))
+		})
+
+		It("should search the track again once found elsewhere", func() {
+			Expect(download()).To(Succeed())
+
+			respond = lyricsFor(nil)
+			Expect(download()).To(Succeed())
+			Expect(track().LyricsStatus).To(Equal(string(music.LyricsStatusFound)))
+		})
+	})
+
+	When("the provider fails", func() {
+		It("should mark the track in error and return the error to retry", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+
+			Expect(download()).ToNot(Succeed())
+			Expect(track().LyricsStatus).To(Equal(string(music.LyricsStatusError)))
+			Expect(outcomes()).To(Equal([]string{string(music.LyricsAttemptError)}))
+			Expect(attempts()[0].Error).ToNot(BeEmpty())
+		})
+	})
+
+	When("the track is instrumental", func() {
+		It("should remember it without writing lyrics", func() {
+			respond = lyricsFor(func(l *lrclib.Lyrics) {
+				l.Instrumental = true
+				l.PlainLyrics, l.SyncedLyrics = "", ""
+			})
+
+			Expect(download()).To(Succeed())
+			Expect(track().LyricsStatus).To(Equal(string(music.LyricsStatusInstrumental)))
+			Expect(track().Instrumental).To(BeTrue())
+			Expect(track().InstrumentalSource).To(Equal(string(music.InstrumentalSourceProvider)))
+			Expect(lyricsPath("txt")).ToNot(BeAnExistingFile())
+
+			Expect(download()).To(Succeed())
+			Expect(requests).To(Equal(1))
+		})
+	})
+
+	When("the lyrics do not match the track", func() {
+		BeforeEach(func() {
+			c.Config.Lyrics.Validation.Enabled = true
+			c.Config.Lyrics.Validation.DurationTolerance = 2 * time.Second
+			respond = lyricsFor(func(l *lrclib.Lyrics) { l.ArtistName = "Spiritbox" })
+		})
+
+		It("should reject them when configured to", func() {
+			c.Config.Lyrics.Validation.Reject = true
+
+			Expect(download()).To(Succeed())
+			Expect(lyricsPath("lrc")).ToNot(BeAnExistingFile())
+			Expect(track().LyricsIssue).ToNot(BeEmpty())
+			Expect(track().LyricsStatus).To(Equal(string(music.LyricsStatusNotFound)))
+			Expect(outcomes()).To(Equal([]string{string(music.LyricsAttemptRejected)}))
+			Expect(attempts()[0].Error).To(Equal(track().LyricsIssue))
+		})
+
+		It("should write and flag them otherwise", func() {
+			Expect(download()).To(Succeed())
+			Expect(lyricsPath("lrc")).To(BeARegularFile())
+			Expect(track().LyricsIssue).ToNot(BeEmpty())
+			Expect(track().LyricsStatus).To(Equal(string(music.LyricsStatusFound)))
+			Expect(outcomes()).To(Equal([]string{string(music.LyricsAttemptHit)}))
+		})
+	})
+
+	When("the track is not persisted yet", func() {
+		It("should write the lyrics without recording attempts", func() {
+			id := track().ID
+			Expect(repo.DeleteTrack(ctx, audioPath)).To(Succeed())
+
+			Expect(download()).To(Succeed())
+			Expect(lyricsPath("lrc")).To(BeARegularFile())
+
+			rows, err := repo.GetLyricsAttemptsByTrackID(ctx, id)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rows).To(BeEmpty())
+		})
+	})
+})
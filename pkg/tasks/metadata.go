@@ -0,0 +1,11 @@
+package tasks
+
+import (
+	"github.com/gerald-lbn/refrain/pkg/music"
+	"github.com/gerald-lbn/refrain/pkg/services"
+)
+
+// extractMetadata extracts the metadata of the audio file at p following the application configuration.
+func extractMetadata(c *services.Container, p string) (*music.Metadata, error) {
+	return music.ExtractMetadata(p, c.MetadataOptions...)
+}
@@ -2,8 +2,13 @@ package tasks
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
 	"time"
 
+	"github.com/gerald-lbn/refrain/pkg/log"
+	"github.com/gerald-lbn/refrain/pkg/lyrics"
 	"github.com/gerald-lbn/refrain/pkg/music"
 	"github.com/gerald-lbn/refrain/pkg/repository"
 	"github.com/gerald-lbn/refrain/pkg/services"
@@ -30,49 +35,401 @@ func (t PersistTrackInfoTask) Config() backlite.QueueConfig {
 	}
 }
 
+// EnqueueMissingEntities enqueues the persistence of the tracks whose tagged artist or album is not
+// linked to them, such as the tracks persisted before artists and albums were stored, and returns how
+// many were enqueued.
+func EnqueueMissingEntities(ctx context.Context, c *services.Container) (int, error) {
+	paths, err := repository.New(c.Database).ListTrackPathsWithoutEntities(ctx)
+	if err != nil || len(paths) == 0 {
+		return 0, err
+	}
+
+	batch := make([]backlite.Task, 0, len(paths))
+	for _, p := range paths {
+		batch = append(batch, PersistTrackInfoTask{Path: p})
+	}
+
+	if _, err := c.Tasks.Add(batch...).Ctx(ctx).Save(); err != nil {
+		return 0, err
+	}
+	return len(paths), nil
+}
+
 func NewPersistTrackInfoQueue(c *services.Container) backlite.Queue {
 	return backlite.NewQueue(func(ctx context.Context, ptit PersistTrackInfoTask) error {
-		if exists := file.Exists(ptit.Path); !exists {
+		audioPath := music.AudioFilePath(ptit.Path)
+		if exists := file.Exists(audioPath); !exists {
 			return nil
 		}
 
-		if isAudio, err := file.IsAudioFile(ptit.Path); err != nil {
+		if isAudio, err := file.IsAudioFile(audioPath); err != nil {
 			return err
 		} else if !isAudio {
 			return nil
 		}
 
-		track, err := music.ExtractMetadata(ptit.Path)
+		// Single-file albums are stored as the tracks of their CUE sheet
+		if music.HasCueSheet(ptit.Path) {
+			return nil
+		}
+
+		track, err := extractMetadata(c, ptit.Path)
+		if err != nil {
+			return err
+		}
+
+		// Cache the cover art thumbnail so the API serves it without decoding the picture
+		if track.HasCover() {
+			if _, err := c.Covers.Thumbnail(track); err != nil {
+				log.Default().Warn("failed to cache cover art",
+					slog.String("path", track.Path),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+
+		tx, err := c.Database.BeginTx(ctx, nil)
 		if err != nil {
 			return err
 		}
+		defer tx.Rollback()
 
-		repo := repository.New(c.Database)
+		repo := repository.New(c.Database).WithTx(tx)
 
 		// Update track info if it already exists
-		if _, err := repo.GetTrackByPath(ctx, track.Path); err == nil {
-			return repo.UpdateTrack(ctx, repository.UpdateTrackParams{
-				Path:            track.Path,
-				Title:           dbUtils.StringToNullString(*track.Title),
-				Artist:          dbUtils.StringToNullString(*track.Artist),
-				Album:           dbUtils.StringToNullString(*track.Album),
-				Duration:        track.Duration,
-				HasPlainLyrics:  track.HasPlainLyrics,
-				HasSyncedLyrics: track.HasSyncedLyrics,
+		if _, err = repo.GetTrackByPath(ctx, track.Path); err == nil {
+			err = repo.UpdateTrack(ctx, repository.UpdateTrackParams{
+				Path:                   track.Path,
+				Title:                  dbUtils.StringPointerToNullString(track.Title),
+				Artist:                 dbUtils.StringPointerToNullString(track.Artist),
+				AlbumArtist:            dbUtils.StringPointerToNullString(track.AlbumArtist),
+				Album:                  dbUtils.StringPointerToNullString(track.Album),
+				Duration:               track.Duration,
+				HasPlainLyrics:         track.HasPlainLyrics,
+				HasSyncedLyrics:        track.HasSyncedLyrics,
+				TrackNumber:            dbUtils.IntToNullInt64(track.TrackNumber),
+				DiscNumber:             dbUtils.IntToNullInt64(track.DiscNumber),
+				ReleaseDate:            dbUtils.StringToNullString(track.ReleaseDate),
+				Year:                   dbUtils.IntToNullInt64(track.Year),
+				Genre:                  dbUtils.StringToNullString(track.Genre),
+				Composer:               dbUtils.StringToNullString(track.Composer),
+				MusicbrainzRecordingID: dbUtils.StringToNullString(track.MusicBrainzRecordingID),
+				MusicbrainzReleaseID:   dbUtils.StringToNullString(track.MusicBrainzReleaseID),
+				Isrc:                   dbUtils.StringToNullString(track.ISRC),
+				Format:                 dbUtils.StringToNullString(track.Format),
+				Bitrate:                dbUtils.IntToNullInt64(track.Bitrate),
+				SampleRate:             dbUtils.IntToNullInt64(track.SampleRate),
+				Channels:               dbUtils.IntToNullInt64(track.Channels),
+				FileSize:               dbUtils.Int64ToNullInt64(track.FileSize),
+				AudioPath:              dbUtils.StringToNullString(track.AudioPath),
+				CueSheetPath:           dbUtils.StringToNullString(track.CueSheetPath),
+				StartOffset:            track.Start,
 			})
+		} else {
+			// Create track if it doesn't exist
+			err = repo.CreateTrack(ctx, repository.CreateTrackParams{
+				Path:                   track.Path,
+				Title:                  dbUtils.StringPointerToNullString(track.Title),
+				Artist:                 dbUtils.StringPointerToNullString(track.Artist),
+				AlbumArtist:            dbUtils.StringPointerToNullString(track.AlbumArtist),
+				Album:                  dbUtils.StringPointerToNullString(track.Album),
+				Duration:               track.Duration,
+				HasPlainLyrics:         track.HasPlainLyrics,
+				HasSyncedLyrics:        track.HasSyncedLyrics,
+				TrackNumber:            dbUtils.IntToNullInt64(track.TrackNumber),
+				DiscNumber:             dbUtils.IntToNullInt64(track.DiscNumber),
+				ReleaseDate:            dbUtils.StringToNullString(track.ReleaseDate),
+				Year:                   dbUtils.IntToNullInt64(track.Year),
+				Genre:                  dbUtils.StringToNullString(track.Genre),
+				Composer:               dbUtils.StringToNullString(track.Composer),
+				MusicbrainzRecordingID: dbUtils.StringToNullString(track.MusicBrainzRecordingID),
+				MusicbrainzReleaseID:   dbUtils.StringToNullString(track.MusicBrainzReleaseID),
+				Isrc:                   dbUtils.StringToNullString(track.ISRC),
+				Format:                 dbUtils.StringToNullString(track.Format),
+				Bitrate:                dbUtils.IntToNullInt64(track.Bitrate),
+				SampleRate:             dbUtils.IntToNullInt64(track.SampleRate),
+				Channels:               dbUtils.IntToNullInt64(track.Channels),
+				FileSize:               dbUtils.Int64ToNullInt64(track.FileSize),
+				AudioPath:              dbUtils.StringToNullString(track.AudioPath),
+				CueSheetPath:           dbUtils.StringToNullString(track.CueSheetPath),
+				StartOffset:            track.Start,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		// Read the track back for its ID and the fields the steps below depend on
+		record, err := repo.GetTrackByPath(ctx, track.Path)
+		if err != nil {
+			return err
+		}
+
+		if err := storeTrackArtists(ctx, repo, record.ID, track); err != nil {
+			return err
+		}
+
+		if err := storeTrackEntities(ctx, repo, record.ID, track); err != nil {
+			return err
+		}
+
+		if err := storeInstrumentalTag(ctx, repo, &record, track); err != nil {
+			return err
+		}
+
+		if err := storeLyrics(ctx, repo, record.ID, track); err != nil {
+			return err
+		}
+
+		if err := storeLyricsStatus(ctx, repo, record); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+}
+
+// storeLyricsStatus updates the lyrics status of the track from the lyrics and flags just stored.
+func storeLyricsStatus(ctx context.Context, repo *repository.Queries, record repository.GetTrackByPathRow) error {
+	edited := false
+	if stored, err := repo.GetLyricsByTrackID(ctx, record.ID); err == nil {
+		edited = stored.Edited
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	status := music.ResolveLyricsStatus(
+		music.LyricsStatus(record.LyricsStatus),
+		record.Instrumental,
+		edited,
+		record.HasPlainLyrics,
+		record.HasSyncedLyrics,
+	)
+	return lyrics.UpdateStatus(ctx, repo, record.ID, status)
+}
+
+// setLyricsStatus moves a persisted track to the given lyrics status. Transitions its current status
+// does not allow, such as searching lyrics of a track ignored meanwhile, are logged and skipped.
+func setLyricsStatus(ctx context.Context, repo *repository.Queries, trackID int64, status music.LyricsStatus) error {
+	if trackID == 0 {
+		return nil
+	}
+
+	err := lyrics.UpdateStatus(ctx, repo, trackID, status)
+	if errors.Is(err, music.ErrInvalidLyricsStatusTransition) {
+		log.Default().Warn("skipping lyrics status update",
+			slog.Int64("track_id", trackID),
+			slog.String("error", err.Error()),
+		)
+		return nil
+	}
+	return err
+}
+
+// storeLyrics records the lyrics found for the track, so they are served without reading its files.
+// Lyrics that changed since they were stored were edited by the user, their provider is kept.
+func storeLyrics(ctx context.Context, repo *repository.Queries, trackID int64, track *music.Metadata) error {
+	plain, synced, embedded, err := track.ReadLyrics()
+	if err != nil {
+		return err
+	}
+
+	if plain == "" && synced == "" {
+		return repo.DeleteLyrics(ctx, trackID)
+	}
+
+	hash := music.LyricsHash(plain, synced)
+	stored, err := repo.GetLyricsByTrackID(ctx, trackID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		provider := music.LyricsProviderSidecar
+		if embedded {
+			provider = music.LyricsProviderEmbedded
 		}
 
-		// Create track if it doesn't exist
-		err = repo.CreateTrack(ctx, repository.CreateTrackParams{
-			Path:            track.Path,
-			Title:           dbUtils.StringToNullString(*track.Title),
-			Artist:          dbUtils.StringToNullString(*track.Artist),
-			Album:           dbUtils.StringToNullString(*track.Album),
-			Duration:        track.Duration,
-			HasPlainLyrics:  track.HasPlainLyrics,
-			HasSyncedLyrics: track.HasSyncedLyrics,
+		return repo.UpsertLyrics(ctx, repository.UpsertLyricsParams{
+			TrackID:      trackID,
+			PlainLyrics:  dbUtils.StringToNullString(plain),
+			SyncedLyrics: dbUtils.StringToNullString(synced),
+			Provider:     string(provider),
+			FetchedAt:    time.Now(),
+			ContentHash:  hash,
 		})
+	case err != nil:
+		return err
+	case stored.ContentHash == hash:
+		return nil
+	}
+
+	log.Default().Info("lyrics edited",
+		slog.String("path", track.Path),
+		slog.String("provider", stored.Provider),
+	)
 
+	return repo.UpsertLyrics(ctx, repository.UpsertLyricsParams{
+		TrackID:      trackID,
+		PlainLyrics:  dbUtils.StringToNullString(plain),
+		SyncedLyrics: dbUtils.StringToNullString(synced),
+		Provider:     stored.Provider,
+		ProviderID:   dbUtils.Int64ToNullInt64(stored.ProviderID),
+		FetchedAt:    stored.FetchedAt,
+		ContentHash:  hash,
+		Edited:       true,
+	})
+}
+
+// storeTrackEntities links the track to its artist and album, creating them when they are new.
+func storeTrackEntities(ctx context.Context, repo *repository.Queries, trackID int64, track *music.Metadata) error {
+	artistID, err := findOrCreateArtist(ctx, repo, track.PrimaryArtist(), track.MusicBrainzArtistID)
+	if err != nil {
 		return err
+	}
+
+	var albumID int64
+	if track.Album != nil && *track.Album != "" {
+		name, musicBrainzID := track.PrimaryAlbumArtist()
+		albumArtistID, err := findOrCreateArtist(ctx, repo, name, musicBrainzID)
+		if err != nil {
+			return err
+		}
+
+		albumID, err = findOrCreateAlbum(ctx, repo, *track.Album, albumArtistID, track.MusicBrainzReleaseID, track.Year)
+		if err != nil {
+			return err
+		}
+	}
+
+	return repo.UpdateTrackEntities(ctx, repository.UpdateTrackEntitiesParams{
+		ArtistID: dbUtils.Int64ToNullInt64(artistID),
+		AlbumID:  dbUtils.Int64ToNullInt64(albumID),
+		ID:       trackID,
 	})
 }
+
+// findOrCreateArtist returns the ID of the artist with the given MusicBrainz ID, or else with the given
+// name, creating it when unknown. Artists sharing a name but having different MusicBrainz IDs are
+// kept apart. It returns zero when the name is empty.
+func findOrCreateArtist(ctx context.Context, repo *repository.Queries, name, musicBrainzID string) (int64, error) {
+	if name == "" {
+		return 0, nil
+	}
+
+	if musicBrainzID != "" {
+		artist, err := repo.GetArtistByMusicBrainzID(ctx, dbUtils.StringToNullString(musicBrainzID))
+		if err == nil {
+			return artist.ID, nil
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+	}
+
+	artist, err := repo.GetArtistByName(ctx, name)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return 0, err
+	case musicBrainzID == "":
+		return artist.ID, nil
+	case !artist.MusicbrainzID.Valid:
+		// The artist was first seen without MusicBrainz ID
+		return artist.ID, repo.UpdateArtistMusicBrainzID(ctx, repository.UpdateArtistMusicBrainzIDParams{
+			MusicbrainzID: dbUtils.StringToNullString(musicBrainzID),
+			ID:            artist.ID,
+		})
+	}
+
+	return repo.CreateArtist(ctx, repository.CreateArtistParams{
+		Name:          name,
+		MusicbrainzID: dbUtils.StringToNullString(musicBrainzID),
+	})
+}
+
+// findOrCreateAlbum returns the ID of the album with the given MusicBrainz ID, or else with the given
+// title and artist, creating it when unknown.
+func findOrCreateAlbum(ctx context.Context, repo *repository.Queries, title string, artistID int64, musicBrainzID string, year int) (int64, error) {
+	if musicBrainzID != "" {
+		album, err := repo.GetAlbumByMusicBrainzID(ctx, dbUtils.StringToNullString(musicBrainzID))
+		if err == nil {
+			return album.ID, nil
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+	}
+
+	album, err := repo.GetAlbumByTitleAndArtist(ctx, repository.GetAlbumByTitleAndArtistParams{
+		Title:    title,
+		ArtistID: dbUtils.Int64ToNullInt64(artistID),
+	})
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return 0, err
+	case musicBrainzID != "" && album.MusicbrainzID.Valid:
+		// Another release of the album, such as a reissue
+	default:
+		// Fill in what the album was first seen without
+		if (musicBrainzID != "" && !album.MusicbrainzID.Valid) || (year != 0 && !album.Year.Valid) {
+			params := repository.UpdateAlbumParams{
+				MusicbrainzID: album.MusicbrainzID,
+				Year:          album.Year,
+				ID:            album.ID,
+			}
+			if musicBrainzID != "" {
+				params.MusicbrainzID = dbUtils.StringToNullString(musicBrainzID)
+			}
+			if !album.Year.Valid {
+				params.Year = dbUtils.IntToNullInt64(year)
+			}
+			err = repo.UpdateAlbum(ctx, params)
+		}
+		return album.ID, err
+	}
+
+	return repo.CreateAlbum(ctx, repository.CreateAlbumParams{
+		Title:         title,
+		ArtistID:      dbUtils.Int64ToNullInt64(artistID),
+		MusicbrainzID: dbUtils.StringToNullString(musicBrainzID),
+		Year:          dbUtils.IntToNullInt64(year),
+	})
+}
+
+// storeInstrumentalTag records the instrumental flag found in the tags of the track, unless the
+// user or the lyrics provider already decided whether it is instrumental. The record is updated
+// along with the database.
+func storeInstrumentalTag(ctx context.Context, repo *repository.Queries, record *repository.GetTrackByPathRow, track *music.Metadata) error {
+	source := music.InstrumentalSource(record.InstrumentalSource)
+	switch {
+	case track.Instrumental && source != music.InstrumentalSourceManual && !record.Instrumental:
+		source = music.InstrumentalSourceTag
+	case !track.Instrumental && source == music.InstrumentalSourceTag:
+		// The tag was removed
+		source = ""
+	default:
+		return nil
+	}
+
+	record.Instrumental, record.InstrumentalSource = track.Instrumental, string(source)
+	return repo.UpdateTrackInstrumentalByPath(ctx, repository.UpdateTrackInstrumentalByPathParams{
+		Instrumental:       track.Instrumental,
+		InstrumentalSource: dbUtils.StringToNullString(string(source)),
+		Path:               track.Path,
+	})
+}
+
+// storeTrackArtists replaces the artists recorded for the track with the ones found in its tags.
+func storeTrackArtists(ctx context.Context, repo *repository.Queries, trackID int64, track *music.Metadata) error {
+	if err := repo.DeleteTrackArtists(ctx, trackID); err != nil {
+		return err
+	}
+
+	for i, artist := range track.Artists {
+		if err := repo.CreateTrackArtist(ctx, repository.CreateTrackArtistParams{
+			TrackID:  trackID,
+			Position: int64(i),
+			Artist:   artist,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package tasks_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+
+	"github.com/gerald-lbn/refrain/pkg/music"
+	"github.com/gerald-lbn/refrain/pkg/repository"
+	"github.com/gerald-lbn/refrain/pkg/services"
+	"github.com/gerald-lbn/refrain/pkg/tasks"
+	dbUtils "github.com/gerald-lbn/refrain/pkg/utils/db"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PersistTrackInfoTask", func() {
+	var (
+		ctx  context.Context
+		c    *services.Container
+		repo *repository.Queries
+	)
+
+	createTrack := func(path, artist, album string) int64 {
+		Expect(repo.CreateTrack(ctx, repository.CreateTrackParams{
+			Path:   path,
+			Artist: dbUtils.StringToNullString(artist),
+			Album:  dbUtils.StringToNullString(album),
+		})).To(Succeed())
+
+		track, err := repo.GetTrackByPath(ctx, path)
+		Expect(err).ToNot(HaveOccurred())
+		return track.ID
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c = newContainer(ctx)
+		repo = repository.New(c.Database)
+	})
+
+	When("persisting a track", func() {
+		var audioPath string
+
+		persist := func() error {
+			return process(ctx, tasks.NewPersistTrackInfoQueue(c), tasks.PersistTrackInfoTask{Path: audioPath})
+		}
+
+		BeforeEach(func() {
+			c.Covers = &music.CoverCache{Directory: GinkgoT().TempDir(), Size: 100}
+			audioPath = copyAudio()
+		})
+
+		It("should store its tags linked to its artist and album", func() {
+			Expect(persist()).To(Succeed())
+
+			track, err := repo.GetTrackByPath(ctx, audioPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(track.Title).To(Equal("Vore"))
+			Expect(track.ArtistID).ToNot(BeZero())
+			Expect(track.AlbumID).ToNot(BeZero())
+			Expect(track.LyricsStatus).To(Equal(string(music.LyricsStatusPending)))
+
+			count, err := tasks.EnqueueMissingEntities(ctx, c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(BeZero())
+		})
+
+		It("should store the lyrics found next to it", func() {
+			lyricsPath := filepath.Join(filepath.Dir(audioPath), "Vore.txt")
+			Expect(os.WriteFile(lyricsPath, []byte("You have become the voice in my head"), 0644)).To(Succeed())
+			Expect(persist()).To(Succeed())
+
+			track, err := repo.GetTrackByPath(ctx, audioPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(track.HasPlainLyrics).To(BeTrue())
+			Expect(track.LyricsStatus).To(Equal(string(music.LyricsStatusPartial)))
+
+			stored, err := repo.GetLyricsByTrackID(ctx, track.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.PlainLyrics).To(Equal("You have become the voice in my head"))
+		})
+
+		It("should keep the outcome of the last search of a track without lyrics", func() {
+			Expect(persist()).To(Succeed())
+			track, err := repo.GetTrackByPath(ctx, audioPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(repo.UpdateTrackLyricsStatus(ctx, repository.UpdateTrackLyricsStatusParams{
+				LyricsStatus: string(music.LyricsStatusNotFound),
+				ID:           track.ID,
+			})).To(Succeed())
+
+			Expect(persist()).To(Succeed())
+			track, err = repo.GetTrackByPath(ctx, audioPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(track.LyricsStatus).To(Equal(string(music.LyricsStatusNotFound)))
+		})
+
+		It("should skip files that no longer exist", func() {
+			Expect(os.Remove(audioPath)).To(Succeed())
+			Expect(persist()).To(Succeed())
+
+			_, err := repo.GetTrackByPath(ctx, audioPath)
+			Expect(err).To(MatchError(sql.ErrNoRows))
+		})
+	})
+
+	When("enqueuing the tracks missing their artist or album", func() {
+		It("should enqueue the tracks whose tags are not linked", func() {
+			createTrack("/music/Sleep Token/Vore.flac", "Sleep Token", "Take Me Back To Eden")
+			createTrack("/music/Spiritbox/Sun Killer.flac", "Spiritbox", "")
+			createTrack("/music/Unknown/track.mp3", "", "")
+
+			count, err := tasks.EnqueueMissingEntities(ctx, c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(Equal(2))
+		})
+
+		It("should skip the tracks already linked", func() {
+			id := createTrack("/music/Sleep Token/Vore.flac", "Sleep Token", "Take Me Back To Eden")
+
+			artistID, err := repo.CreateArtist(ctx, repository.CreateArtistParams{Name: "Sleep Token"})
+			Expect(err).ToNot(HaveOccurred())
+			albumID, err := repo.CreateAlbum(ctx, repository.CreateAlbumParams{
+				Title:    "Take Me Back To Eden",
+				ArtistID: dbUtils.Int64ToNullInt64(artistID),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(repo.UpdateTrackEntities(ctx, repository.UpdateTrackEntitiesParams{
+				ArtistID: dbUtils.Int64ToNullInt64(artistID),
+				AlbumID:  dbUtils.Int64ToNullInt64(albumID),
+				ID:       id,
+			})).To(Succeed())
+
+			count, err := tasks.EnqueueMissingEntities(ctx, c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(BeZero())
+		})
+	})
+})
@@ -1,8 +1,18 @@
 package tasks_test
 
 import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/gerald-lbn/refrain/config"
+	"github.com/gerald-lbn/refrain/pkg/services"
+	"github.com/gerald-lbn/refrain/pkg/tasks"
+	"github.com/gerald-lbn/refrain/pkg/utils/db/dbtest"
+	"github.com/mikestefanello/backlite"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -11,3 +21,38 @@ func TestTasks(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Tasks Suite")
 }
+
+// newContainer returns a container backed by a migrated database in a temporary directory, whose
+// queues are registered but never started.
+func newContainer(ctx context.Context) *services.Container {
+	database := dbtest.New(ctx)
+
+	client, err := backlite.NewClient(backlite.ClientConfig{
+		DB:           database,
+		NumWorkers:   1,
+		ReleaseAfter: time.Minute,
+	})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(client.Install()).To(Succeed())
+
+	c := &services.Container{Config: &config.Config{}, Database: database, Tasks: client}
+	tasks.Register(c)
+	return c
+}
+
+// process runs the task as the queue would, returning the error the task would be retried on.
+func process(ctx context.Context, queue backlite.Queue, task backlite.Task) error {
+	payload, err := json.Marshal(task)
+	Expect(err).ToNot(HaveOccurred())
+	return queue.Process(ctx, payload)
+}
+
+// copyAudio copies the test audio file to a temporary directory and returns its path.
+func copyAudio() string {
+	data, err := os.ReadFile("../test_data/Vore.flac")
+	Expect(err).ToNot(HaveOccurred())
+
+	p := filepath.Join(GinkgoT().TempDir(), "Vore.flac")
+	Expect(os.WriteFile(p, data, 0644)).To(Succeed())
+	return p
+}
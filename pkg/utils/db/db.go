@@ -10,6 +10,27 @@ func StringToNullString(s string) sql.NullString {
 	return sql.NullString{String: s, Valid: true}
 }
 
+// StringPointerToNullString converts an optional string to a sql.NullString.
+func StringPointerToNullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return StringToNullString(*s)
+}
+
+// IntToNullInt64 converts an int to a sql.NullInt64, zero being considered as null.
+func IntToNullInt64(i int) sql.NullInt64 {
+	return Int64ToNullInt64(int64(i))
+}
+
+// Int64ToNullInt64 converts an int64 to a sql.NullInt64, zero being considered as null.
+func Int64ToNullInt64(i int64) sql.NullInt64 {
+	if i == 0 {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: i, Valid: true}
+}
+
 // Like returns a LIKE pattern for the given value.
 func Like(value string) string {
 	return "%" + value + "%"
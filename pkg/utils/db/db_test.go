@@ -20,6 +20,16 @@ var _ = Describe("Db", func() {
 		})
 	})
 
+	Context("IntToNullInt64", func() {
+		It("should convert zero to a null int", func() {
+			Expect(db.IntToNullInt64(0)).To(Equal(sql.NullInt64{}))
+		})
+
+		It("should convert a non-zero int to a non-null int", func() {
+			Expect(db.IntToNullInt64(5)).To(Equal(sql.NullInt64{Int64: 5, Valid: true}))
+		})
+	})
+
 	Context("Like", func() {
 		It("should return a LIKE pattern with wildcards", func() {
 			Expect(db.Like("abc")).To(Equal("%abc%"))
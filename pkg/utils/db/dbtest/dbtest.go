@@ -0,0 +1,28 @@
+// Package dbtest provides the databases used by the specs.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+
+	"github.com/gerald-lbn/refrain/db"
+	"github.com/gerald-lbn/refrain/pkg/migrate"
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// New opens a migrated database in a temporary directory, closed when the spec ends.
+func New(ctx context.Context) *sql.DB {
+	database, err := sql.Open("sqlite3", filepath.Join(GinkgoT().TempDir(), "refrain.db"))
+	Expect(err).ToNot(HaveOccurred())
+	DeferCleanup(database.Close)
+
+	migrations, err := migrate.Load(db.Migrations, "migrations")
+	Expect(err).ToNot(HaveOccurred())
+	_, err = migrate.New(database, migrations).Up(ctx)
+	Expect(err).ToNot(HaveOccurred())
+
+	return database
+}